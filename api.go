@@ -1,9 +1,13 @@
 package sgin
 
 import (
+	"fmt"
 	"net/http"
 	"reflect"
+	"regexp"
+	"slices"
 	"strings"
+	"unicode"
 
 	"github.com/baagod/sgin/v2/helper"
 )
@@ -20,8 +24,46 @@ func isFileType(t reflect.Type) bool {
 // API 持有 OpenAPI 生成过程中的所有可配置策略
 type API struct {
 	*OpenAPI
+	ProblemResponses *bool                                            // 是否为每个操作记录 RFC 9457 错误响应，为 nil 时跟随 Config.Problem。
+	OperationID      func(method, path string, arg *HandleArg) string // 为未指定 operationId 的操作生成标识，默认 DefaultOperationID。
+	codecs           *codecs                                          // 引擎中已注册的编解码器，决定请求和响应的媒体类型。
+	envelope         *EnvelopeConfig                                  // 引擎的统一响应包装，文档记录包装后的结构。
+	page             *PageConfig                                      // 引擎的分页配置，文档记录 size 参数的默认值和最大值。
+	operationIDs     map[string]bool                                  // 已使用的 operationId
 }
 
+// DefaultOperationID 以请求方法和路径生成 operationId，如 GET /users/:id/orders -> getUsersByIdOrders。
+func DefaultOperationID(method, path string, _ *HandleArg) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for seg := range strings.SplitSeq(path, "/") {
+		if name, ok := strings.CutPrefix(seg, ":"); ok {
+			seg = "by-" + name
+		} else if name, ok = strings.CutPrefix(seg, "*"); ok {
+			seg = "by-" + name
+		}
+		for word := range strings.FieldsFuncSeq(seg, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			b.WriteString(helper.UpperFirst(word))
+		}
+	}
+	return b.String()
+}
+
+// FuncOperationID 以处理器函数的名称生成 operationId，如 (*UserService).GetUser -> getUser，
+// 匿名函数使用 DefaultOperationID。
+func FuncOperationID(method, path string, arg *HandleArg) string {
+	name := strings.ReplaceAll(strings.TrimSuffix(arg.Func, "-fm"), "[...]", "") // 方法值和泛型函数
+	name = name[strings.LastIndexByte(name, '.')+1:]
+	if name == "" || anonymousFunc.MatchString(name) {
+		return DefaultOperationID(method, path, arg)
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+var anonymousFunc = regexp.MustCompile(`^(func)?\d+$`) // 如 main.main.func1、main.main.func1.2
+
 func NewAPI(f ...func(*API)) *API {
 	c := &API{
 		OpenAPI: &OpenAPI{
@@ -38,6 +80,8 @@ func NewAPI(f ...func(*API)) *API {
 			},
 			tagMap: map[string]bool{},
 		},
+		OperationID:  DefaultOperationID,
+		operationIDs: map[string]bool{},
 	}
 
 	if len(f) > 0 {
@@ -47,10 +91,23 @@ func NewAPI(f ...func(*API)) *API {
 	return c
 }
 
+// mediaTypes 返回文档中类型 t 的请求体和响应体使用的媒体类型
+func (a *API) mediaTypes(t reflect.Type) []string {
+	if a.codecs == nil || len(a.codecs.docs) == 0 {
+		return []string{MIMEJSON}
+	}
+	return a.codecs.docsFor(t)
+}
+
 func (a *API) Schema(t reflect.Type, hint ...string) *Schema {
 	return a.Components.Schemas.Schema(t, hint...)
 }
 
+// bodySchema 返回请求体或响应体的 Schema，proto.Message 按 protojson 的映射生成。
+func (a *API) bodySchema(t reflect.Type) *Schema {
+	return a.Components.Schemas.bodySchema(t)
+}
+
 func (a *API) Struct(t reflect.Type, hint ...string) *Schema {
 	return a.Components.Schemas.Struct(t, hint...)
 }
@@ -70,29 +127,88 @@ func (a *API) Register(op *Operation, path, method string, arg *HandleArg) {
 		op.Responses = map[string]*ResponseBody{}
 	}
 
-	a.parseRequestParams(op, arg.In)      // 解析结构体标签并映射为请求参数或 RequestBody
-	a.parseResponseBody(op, arg.Out)      // 解析返回类型并映射为 ResponseBody
+	a.parseRequestParams(op, arg.In) // 解析结构体标签并映射为请求参数或 RequestBody
+
+	if len(arg.Messages) == 2 {
+		a.parseWebSocket(op, arg) // WebSocket 端点以扩展描述消息类型
+	} else {
+		a.parseResponseBody(op, arg) // 解析返回类型并映射为 ResponseBody
+	}
+
+	if a.envelope != nil && !op.RawResponse {
+		a.parseEnvelopeError(op) // 错误以包装结构响应
+	} else if a.ProblemResponses != nil && *a.ProblemResponses {
+		a.parseProblem(op) // 错误以问题详情响应
+	}
+
+	a.setOperationID(op, path, method, arg)
 	a.registerOperation(op, path, method) // 将配置好的 Operation 绑定到 OpenAPI 路径树中
 }
 
+// setOperationID 为 Operation 生成唯一的 operationId，生成的标识重复时依次添加数字后缀。
+func (a *API) setOperationID(op *Operation, path, method string, arg *HandleArg) {
+	if a.operationIDs == nil {
+		a.operationIDs = map[string]bool{}
+	}
+
+	if op.OperationID != "" {
+		if a.operationIDs[op.OperationID] {
+			panic(fmt.Errorf("sgin: duplicate operationId %q for %s %s", op.OperationID, method, path))
+		}
+		a.operationIDs[op.OperationID] = true
+		return
+	}
+
+	if a.OperationID == nil {
+		return
+	}
+
+	base := a.OperationID(method, path, arg)
+	id := base
+	for i := 2; a.operationIDs[id]; i++ {
+		id = fmt.Sprintf("%s%d", base, i)
+	}
+	a.operationIDs[id] = true
+	op.OperationID = id
+}
+
 // parseRequestParams 解析输入标签 (uri, form, header, json) 并映射为 OpenAPI 的参数或请求体
 func (a *API) parseRequestParams(op *Operation, t reflect.Type) {
+	if isProto(t) { // 整个请求体使用 protojson 或 Protobuf 解码，字段不映射为参数。
+		content := map[string]*MediaType{}
+		for _, m := range a.mediaTypes(t) {
+			content[m] = &MediaType{Schema: a.bodySchema(t)}
+		}
+		op.RequestBody = &RequestBody{Content: content}
+		return
+	}
+
 	t = helper.Deref(t)
 	if t.Kind() != reflect.Struct {
 		return
 	}
 
 	var body []reflect.StructField // 用于收集映射到 RequestBody 的字段
-	mime := MIMEJSON               // 默认媒体类型
+	mime := ""                     // 为空时使用已注册编解码器的媒体类型
 
-	for i := range t.NumField() {
-		f := t.Field(i)
-		desc := f.Tag.Get("doc")                                       // 获取描述
-		required := strings.Contains(f.Tag.Get("binding"), "required") // 检查是否必填
+	for info := range getFields(t) { // 内嵌结构体 (如 PageQuery) 的字段展开处理
+		f := info.Name
+		if !f.IsExported() || f.Tag.Get("hidden") == "true" { // 非导出字段不参与绑定 (如 proto.Message 的内部状态)，hidden 字段不出现在文档中。
+			continue
+		}
+
+		// 排序、过滤和字段选择参数自行描述 (如 ListQuery)
+		if p, ok := reflect.Zero(f.Type).Interface().(queryParam); ok {
+			op.Parameters = append(op.Parameters, p.openAPIParam(a))
+			continue
+		}
+
+		desc := f.Tag.Get("doc")                          // 获取描述
+		required := bindingRequired(f.Tag.Get("binding")) // 检查是否必填
 
 		// 1. 处理路径参数 (uri 标签) -> 映射至 OpenAPI path 参数
 		if tag := f.Tag.Get("uri"); tag != "" {
-			a.addParam(op, tag, "path", desc, true, f.Type)
+			a.addParam(op, tag, "path", desc, true, f)
 			continue
 		}
 
@@ -103,14 +219,17 @@ func (a *API) parseRequestParams(op *Operation, t reflect.Type) {
 				body = append(body, f)
 				mime = MIMEMultipartForm
 			} else {
-				a.addParam(op, tag, "query", desc, required, f.Type)
+				a.addParam(op, tag, "query", desc, required, f)
+				if tag == "size" && pageQueryTypes[info.Parent] {
+					a.pageSizeSchema(op.Parameters[len(op.Parameters)-1].Schema)
+				}
 			}
 			continue
 		}
 
 		// 3. 处理请求头参数 (header 标签) -> 映射至 OpenAPI header 参数
 		if tag := f.Tag.Get("header"); tag != "" {
-			a.addParam(op, tag, "header", desc, required, f.Type)
+			a.addParam(op, tag, "header", desc, required, f)
 			continue
 		}
 
@@ -144,50 +263,96 @@ func (a *API) parseRequestParams(op *Operation, t reflect.Type) {
 
 		if fs := a.Field(f, t.Name()+f.Name); fs != nil {
 			props[name] = fs
-			if strings.Contains(f.Tag.Get("binding"), "required") {
+			if bindingRequired(f.Tag.Get("binding")) {
 				required = append(required, name)
 			}
 		}
 	}
 
 	// 注入到 Operation 的 RequestBody 中
-	op.RequestBody = &RequestBody{
-		Content: map[string]*MediaType{
-			mime: {
-				Schema: &Schema{Type: TypeObject, Properties: props, Required: required},
-			},
-		},
-		Required: len(required) > 0,
+	media := a.mediaTypes(t)
+	if mime != "" {
+		media = []string{mime}
 	}
+
+	schema := &Schema{Type: TypeObject, Properties: props, Required: required}
+	content := map[string]*MediaType{}
+	for _, m := range media {
+		content[m] = &MediaType{Schema: schema}
+	}
+
+	op.RequestBody = &RequestBody{Content: content, Required: len(required) > 0}
 }
 
 // addParam 辅助方法：向 Operation 中添加一个新的参数描述 (path, query, header 等)
-func (a *API) addParam(op *Operation, name, in, desc string, required bool, t reflect.Type) {
+func (a *API) addParam(op *Operation, name, in, desc string, required bool, f reflect.StructField) {
+	schema := a.Field(f, f.Name) // 自动解析字段对应的 JSON Schema (包括 default, enum 等标签)
+	if schema != nil {
+		schema.Description = "" // 描述已在参数中
+	}
+
 	op.Parameters = append(op.Parameters, &Param{
 		Name:        name,
 		In:          in,
 		Required:    required,
 		Description: desc,
-		Schema:      a.Schema(t), // 自动解析类型对应的 JSON Schema
+		Schema:      schema,
 	})
 }
 
 // parseResponseBody 解析处理器的返回值类型，并根据需要自动注入默认的 200 响应。
-func (a *API) parseResponseBody(op *Operation, t reflect.Type) {
+func (a *API) parseResponseBody(op *Operation, arg *HandleArg) {
 	// 仅当用户未在路由定义中显式通过 AddOperation 自定义 200 响应时，才执行自动注入。
 	if _, ok := op.Responses["200"]; ok {
 		return
 	}
 
 	// 如果处理器没有返回值，注入一个不带 Body 的 200 响应。
+	t := arg.Out
 	if t == nil {
 		op.Responses["200"] = &ResponseBody{}
 		return
 	}
 
-	// 否则，解析返回类型并生成 application/json 响应
-	op.Responses["200"] = &ResponseBody{
-		Content: map[string]*MediaType{MIMEJSON: {Schema: a.Schema(t)}},
+	// 否则，解析返回类型并按声明的媒体类型 (默认为已注册的编解码器) 生成响应
+	media := arg.Media
+	if len(media) == 0 {
+		if media = a.mediaTypes(t); !arg.Stream {
+			if _, ok := csvElem(t); ok { // 结构体列表支持导出 CSV
+				media = append(slices.Clone(media), MIMECSV)
+			}
+		}
+	}
+
+	content := map[string]*MediaType{}
+	for _, m := range media {
+		// 流式响应的 Out 为元素类型，JSON 和 CSV 中表现为数组，NDJSON 和 SSE 中每行/每个事件为一个元素。
+		switch {
+		case arg.Stream && (m == MIMEJSON || m == MIMECSV):
+			content[m] = &MediaType{Schema: &Schema{Type: TypeArray, Items: a.bodySchema(t)}}
+		case a.envelope != nil && !op.RawResponse && !arg.Stream && m != MIMECSV:
+			content[m] = &MediaType{Schema: a.envelopeSchema(t)} // 流式响应和 CSV 导出不包装
+		default:
+			content[m] = &MediaType{Schema: a.bodySchema(t)}
+		}
+	}
+
+	op.Responses["200"] = &ResponseBody{Content: content}
+}
+
+// parseWebSocket 为 WebSocket 端点注入 101 响应，并通过 x-websocket 扩展描述收发的消息结构。
+func (a *API) parseWebSocket(op *Operation, arg *HandleArg) {
+	if _, ok := op.Responses["101"]; !ok {
+		op.Responses["101"] = &ResponseBody{Description: "Switching Protocols"}
+	}
+
+	if op.Extensions == nil {
+		op.Extensions = map[string]any{}
+	}
+
+	op.Extensions["x-websocket"] = map[string]*Schema{
+		"receive": a.bodySchema(arg.Messages[0]), // 客户端发送的消息
+		"send":    a.bodySchema(arg.Messages[1]), // 服务端发送的消息
 	}
 }
 
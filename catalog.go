@@ -0,0 +1,191 @@
+package sgin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/goccy/go-yaml"
+	"golang.org/x/text/language"
+)
+
+// Catalog 是按键注册的多语言消息目录，*Error 通过 Key 引用其中的消息。
+//
+// 文件格式为 "键 -> 语言 -> 消息模板"，模板使用 text/template 语法，参数来自 Error.Args：
+//
+//	user.not_found:
+//	  zh: 用户 {{.id}} 不存在
+//	  en: User {{.id}} not found
+//	http.404:          # "http.<状态码>" 用于翻译未自定义消息的 HTTP 错误
+//	  zh: 资源不存在
+type Catalog struct {
+	mu       sync.RWMutex
+	fallback language.Tag
+	messages map[string]map[language.Tag]*template.Template
+}
+
+// NewCatalog 创建消息目录，fallback 为请求语言没有对应消息时使用的默认语言。
+func NewCatalog(fallback language.Tag) *Catalog {
+	return &Catalog{fallback: fallback, messages: map[string]map[language.Tag]*template.Template{}}
+}
+
+// Add 注册 key 在 tag 语言下的消息模板
+func (cat *Catalog) Add(key string, tag language.Tag, message string) error {
+	tmpl, err := template.New(key).Option("missingkey=zero").Parse(message)
+	if err != nil {
+		return fmt.Errorf("sgin: invalid message %q for %s: %w", key, tag, err)
+	}
+
+	cat.mu.Lock()
+	defer cat.mu.Unlock()
+
+	if cat.messages[key] == nil {
+		cat.messages[key] = map[language.Tag]*template.Template{}
+	}
+	cat.messages[key][tag] = tmpl
+	return nil
+}
+
+// LoadFile 从 YAML (.yaml/.yml) 或 JSON (.json) 文件加载消息
+func (cat *Catalog) LoadFile(name string) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	return cat.load(filepath.Ext(name), data)
+}
+
+// LoadFS 从文件系统 (如 embed.FS) 加载所有匹配 pattern 的消息文件
+func (cat *Catalog) LoadFS(fsys fs.FS, pattern string) error {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		if err = cat.load(path.Ext(name), data); err != nil {
+			return fmt.Errorf("sgin: load %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (cat *Catalog) load(ext string, data []byte) (err error) {
+	var messages map[string]map[string]string
+	switch strings.ToLower(ext) {
+	case ".json":
+		err = json.Unmarshal(data, &messages)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &messages)
+	default:
+		return fmt.Errorf("sgin: unsupported catalog file type %q", ext)
+	}
+	if err != nil {
+		return err
+	}
+
+	for key, langs := range messages {
+		for lang, message := range langs {
+			tag, err := language.Parse(lang)
+			if err != nil {
+				return fmt.Errorf("sgin: invalid language %q for %s: %w", lang, key, err)
+			}
+			if err = cat.Add(key, tag, message); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Message 按 tag 渲染 key 对应的消息，依次尝试 tag、去掉扩展的 tag、基础语言和默认语言。
+func (cat *Catalog) Message(tag language.Tag, key string, args map[string]any) (string, bool) {
+	cat.mu.RLock()
+	langs := cat.messages[key]
+	cat.mu.RUnlock()
+
+	if len(langs) == 0 {
+		return "", false
+	}
+
+	base, _ := tag.Base()
+	region, _ := tag.Region()
+	regional, _ := language.Compose(base, region)
+	plain, _ := language.Compose(base)
+
+	for _, t := range []language.Tag{tag, regional, plain, cat.fallback} {
+		tmpl, ok := langs[t]
+		if !ok {
+			continue
+		}
+
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, args); err == nil {
+			return sb.String(), true
+		}
+	}
+
+	return "", false
+}
+
+// Translate 按当前请求的语言渲染消息目录中 key 对应的消息，找不到时返回 key。
+func (c *Ctx) Translate(key string, args ...map[string]any) string {
+	if cat := c.engine.cfg.Catalog; cat != nil {
+		var arg map[string]any
+		if len(args) > 0 {
+			arg = args[0]
+		}
+		if msg, ok := cat.Message(c.locale(), key, arg); ok {
+			return msg
+		}
+	}
+	return key
+}
+
+// errorMessage 返回 err 在当前请求语言下的消息：
+// *Error 设置了 Key 时使用目录中的消息，未自定义消息的 HTTP 错误使用 "http.<状态码>"。
+func (c *Ctx) errorMessage(err error) string {
+	var e *Error
+	cat := c.engine.cfg.Catalog
+	if cat == nil || !errors.As(err, &e) {
+		return err.Error()
+	}
+
+	key := e.Key
+	if key == "" && e.Message == http.StatusText(e.Code) {
+		key = "http." + strconv.Itoa(e.Code)
+	}
+	if key == "" {
+		return err.Error()
+	}
+
+	if msg, ok := cat.Message(c.locale(), key, e.Args); ok {
+		return msg
+	}
+	return err.Error()
+}
+
+// statusTitle 返回状态码在当前请求语言下的描述
+func (c *Ctx) statusTitle(code int) string {
+	if cat := c.engine.cfg.Catalog; cat != nil {
+		if msg, ok := cat.Message(c.locale(), "http."+strconv.Itoa(code), nil); ok {
+			return msg
+		}
+	}
+	return http.StatusText(code)
+}
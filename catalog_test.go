@@ -0,0 +1,133 @@
+package sgin
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/language"
+)
+
+func testCatalog(t *testing.T) *Catalog {
+	t.Helper()
+	cat := NewCatalog(language.English)
+	err := cat.LoadFS(fstest.MapFS{
+		"i18n/user.yaml": {Data: []byte("user.not_found:\n  en: User {{.id}} not found\n  zh: 用户 {{.id}} 不存在\n  zh-TW: 用戶 {{.id}} 不存在\n")},
+		"i18n/http.json": {Data: []byte(`{"http.404": {"zh": "资源不存在"}, "http.500": {"zh": "服务器错误"}}`)},
+		"i18n/README.md": {Data: []byte("not a catalog")},
+	}, "i18n/*.[jy]*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cat
+}
+
+func TestCatalogMessage(t *testing.T) {
+	cat := testCatalog(t)
+	args := map[string]any{"id": 7}
+
+	tests := []struct {
+		tag  language.Tag
+		want string
+	}{
+		{language.Chinese, "用户 7 不存在"},
+		{language.TraditionalChinese, "用戶 7 不存在"},             // zh-Hant 推断地区为 TW
+		{language.MustParse("zh-TW"), "用戶 7 不存在"},             // 精确匹配
+		{language.MustParse("zh-TW-u-co-stroke"), "用戶 7 不存在"}, // 去掉扩展
+		{language.MustParse("zh-CN"), "用户 7 不存在"},             // 去掉地区
+		{language.MustParse("en-GB"), "User 7 not found"},
+		{language.Japanese, "User 7 not found"}, // 回退到默认语言
+		{language.Und, "User 7 not found"},
+	}
+	for _, tt := range tests {
+		if msg, ok := cat.Message(tt.tag, "user.not_found", args); !ok || msg != tt.want {
+			t.Errorf("Message(%s) = %q, %v, want %q", tt.tag, msg, ok, tt.want)
+		}
+	}
+
+	// 缺少的参数渲染为零值
+	if msg, _ := cat.Message(language.English, "user.not_found", nil); msg != "User <no value> not found" {
+		t.Errorf("missing arg = %q", msg)
+	}
+
+	// 未注册的键，或请求语言和默认语言都没有消息。
+	if _, ok := cat.Message(language.English, "user.unknown", nil); ok {
+		t.Error("unknown key found")
+	}
+	if _, ok := cat.Message(language.English, "http.404", nil); ok {
+		t.Error("http.404 has no en or fallback message")
+	}
+}
+
+func TestCatalogLoadErrors(t *testing.T) {
+	cat := NewCatalog(language.English)
+	if err := cat.Add("bad", language.English, "{{.id"); err == nil {
+		t.Error("invalid template accepted")
+	}
+
+	for name, data := range map[string]string{
+		"a.txt":  "key:\n  en: x\n",
+		"b.yaml": "key:\n  \"en_!\": x\n",
+		"c.json": "{",
+	} {
+		fsys := fstest.MapFS{name: {Data: []byte(data)}}
+		if err := cat.LoadFS(fsys, name); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+
+	if err := cat.LoadFile("testdata/missing.yaml"); err == nil {
+		t.Error("missing file loaded")
+	}
+}
+
+func TestCatalogErrorHandler(t *testing.T) {
+	cat := testCatalog(t)
+	handler := func(c *Ctx) error {
+		return ErrNotFound().WithKey("user.not_found", map[string]any{"id": 7})
+	}
+
+	for _, cfg := range []Config{
+		{Catalog: cat, Locales: []language.Tag{language.English, language.Chinese}},
+		{Catalog: cat, Locales: []language.Tag{language.English, language.Chinese}, Problem: true},
+	} {
+		e := testEngine(cfg)
+		e.GET("/users/:id", He(handler))
+		e.GET("/plain", He(func(c *Ctx) error { return ErrNotFound() }))
+		e.GET("/custom", He(func(c *Ctx) error { return ErrNotFound("no such page") }))
+		e.GET("/translate", He(func(c *Ctx) error {
+			return c.Send(c.Translate("user.not_found", map[string]any{"id": 1}) + "|" + c.Translate("user.unknown"))
+		}))
+
+		tests := []struct {
+			target string
+			header []string
+			want   string
+		}{
+			{"/users/7", nil, "User 7 not found"}, // 默认使用 Locales 中的第一个语言
+			{"/users/7", []string{HeaderAcceptLanguage, "zh-CN,zh;q=0.9,en;q=0.8"}, "用户 7 不存在"},
+			{"/users/7", []string{HeaderAcceptLanguage, "fr;q=0.9,zh;q=0.5"}, "用户 7 不存在"},
+			{"/users/7?lang=zh-TW", []string{HeaderAcceptLanguage, "en"}, "用戶 7 不存在"}, // 查询参数优先
+			{"/plain", []string{HeaderAcceptLanguage, "zh"}, "资源不存在"},                 // http.<状态码>
+			{"/plain", nil, http.StatusText(http.StatusNotFound)},
+			{"/custom", []string{HeaderAcceptLanguage, "zh"}, "no such page"}, // 自定义消息不翻译
+			{"/translate", []string{HeaderAcceptLanguage, "zh"}, "用户 1 不存在|user.unknown"},
+		}
+		for _, tt := range tests {
+			w := serve(e, http.MethodGet, tt.target, nil, tt.header...)
+			if !strings.Contains(w.Body.String(), tt.want) {
+				t.Errorf("problem=%v %s %v: body = %s, want %q", cfg.Problem, tt.target, tt.header, w.Body.String(), tt.want)
+			}
+		}
+	}
+
+	// 未配置目录时使用 Error 自身的消息
+	e := testEngine()
+	e.GET("/users/:id", He(handler))
+	w := serve(e, http.MethodGet, "/users/7", nil, HeaderAcceptLanguage, "zh")
+	expectStatus(t, w, http.StatusNotFound)
+	if !strings.Contains(w.Body.String(), http.StatusText(http.StatusNotFound)) {
+		t.Fatalf("body = %s", w.Body.String())
+	}
+}
@@ -0,0 +1,142 @@
+// openapi 在不启动服务器的情况下导出 sgin 应用的 OpenAPI 文档。
+//
+// 它在当前模块中生成一个临时程序，调用指定包中返回 *sgin.Engine 的函数 (只注册路由，不调用 Run)，
+// 并将 Engine.Spec() 写入文件：
+//
+//	// internal/server/routes.go
+//	func Routes() *sgin.Engine {
+//	    r := sgin.New(sgin.Config{OpenAPI: sgin.NewAPI()})
+//	    r.GET("/users/:id", sgin.H(getUser))
+//	    return r
+//	}
+//
+//	go run github.com/baagod/sgin/v2/cmd/openapi -pkg ./internal/server -func Routes -o openapi.json
+//
+// 输出文件的扩展名为 .json 时使用 JSON 格式，否则使用 YAML 格式。
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+)
+
+var program = template.Must(template.New("main").Parse(`// Code generated by sgin openapi. DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	target {{printf "%q" .Import}}
+)
+
+func main() {
+	if err := target.{{.Func}}().WriteSpec({{printf "%q" .Out}}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`))
+
+func main() {
+	pkg := flag.String("pkg", ".", "包含路由注册函数的包 (导入路径或相对目录)，不能是 main 包。")
+	fn := flag.String("func", "Routes", "返回 *sgin.Engine 的函数名称")
+	out := flag.String("o", "openapi.yaml", "输出文件，扩展名为 .json 时使用 JSON 格式。")
+	flag.Parse()
+
+	if err := run(*pkg, *fn, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "openapi:", err)
+		os.Exit(1)
+	}
+}
+
+// listPackage 是 go list -json 输出中需要的字段
+type listPackage struct {
+	ImportPath string
+	Name       string
+	Module     *struct{ Dir string } // 不在模块中时为 nil
+}
+
+func run(pkg, fn, out string) error {
+	b, err := goList("-json", pkg)
+	if err != nil {
+		return err
+	}
+
+	var p listPackage
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if err = dec.Decode(&p); err != nil {
+		return fmt.Errorf("go list: %w", err)
+	}
+	if dec.More() {
+		return fmt.Errorf("%s matches more than one package", pkg)
+	}
+	if p.Module == nil || p.Module.Dir == "" {
+		return fmt.Errorf("package %s is not in a module", p.ImportPath)
+	}
+	if p.Name == "main" {
+		return errors.New("cannot import a main package, move the route setup into another package")
+	}
+
+	if out, err = filepath.Abs(out); err != nil {
+		return err
+	}
+
+	// 收到中断信号时结束子进程并返回，使临时目录得到清理。
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// 临时程序需要放在模块内，才能导入模块中的内部包。
+	dir, err := os.MkdirTemp(p.Module.Dir, "_sgin_openapi_")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	var src bytes.Buffer
+	if err = program.Execute(&src, map[string]string{"Import": p.ImportPath, "Func": fn, "Out": out}); err != nil {
+		return err
+	}
+	if err = os.WriteFile(filepath.Join(dir, "main.go"), src.Bytes(), 0o644); err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "run", "./"+filepath.Base(dir))
+	cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+	cmd.WaitDelay = 5 * time.Second // 子进程未响应中断时强制结束
+	cmd.Dir = p.Module.Dir
+	cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr // 路由注册时的调试输出不影响结果
+	if err = cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return errors.New("interrupted")
+		}
+		return err
+	}
+
+	fmt.Println("openapi: wrote", out)
+	return nil
+}
+
+func goList(args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", append([]string{"list"}, args...)...)
+	cmd.Stderr = &stderr
+	b, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list: %s", strings.TrimSpace(stderr.String()))
+	}
+	return b, nil
+}
@@ -0,0 +1,212 @@
+package sgin
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/pelletier/go-toml/v2"
+	"google.golang.org/protobuf/proto"
+)
+
+var protoMessageType = reflect.TypeFor[proto.Message]()
+
+// Codec 负责一种媒体类型的请求体解码和响应体编码
+type Codec interface {
+	Decode(data []byte, v any) error // 将请求体解码到 v (指针)
+	Encode(w io.Writer, v any) error // 将 v 编码并写入响应体
+}
+
+// TypedCodec 是仅支持部分类型的编解码器 (如 Protobuf)，
+// 请求绑定、响应内容协商和 OpenAPI 文档只对 Supports 返回 true 的类型使用它。
+// proto.Message 只使用 JSON (protojson) 和支持它的 TypedCodec，其他编解码器会处理生成代码的内部结构。
+type TypedCodec interface {
+	Codec
+	Supports(t reflect.Type) bool
+}
+
+// codecs 按注册顺序保存媒体类型与编解码器的映射
+type codecs struct {
+	m      map[string]Codec
+	offers []string // 内容协商时的候选媒体类型 (按注册顺序)
+	docs   []string // 写入 OpenAPI 文档的媒体类型 (不含别名)
+}
+
+func newCodecs(json *jsonCodec) *codecs {
+	cs := &codecs{m: map[string]Codec{}}
+	cs.add(MIMEJSON, json, true)
+	cs.add(MIMEXML, xmlCodec{}, true)
+	cs.add(MIMETextXML, xmlCodec{}, false)
+	cs.add(MIMEYAML, yamlCodec{}, true)
+	cs.add(MIMEYAMLX, yamlCodec{}, false)
+	cs.add(MIMETOML, tomlCodec{}, true)
+	cs.add(MIMEProtobufX, protoCodec{}, true)
+	cs.add(MIMEProtobuf, protoCodec{}, false)
+	return cs
+}
+
+func (cs *codecs) add(mime string, codec Codec, doc bool) {
+	if _, ok := cs.m[mime]; !ok {
+		cs.offers = append(cs.offers, mime)
+		if doc {
+			cs.docs = append(cs.docs, mime)
+		}
+	}
+	cs.m[mime] = codec
+}
+
+// get 根据 Content-Type (可带参数) 查找编解码器
+func (cs *codecs) get(contentType string) Codec {
+	if cs == nil || contentType == "" {
+		return nil
+	}
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mt
+	}
+	return cs.m[contentType]
+}
+
+// supports 报告 codec 是否可以编解码类型 t
+func supports(codec Codec, t reflect.Type) bool {
+	if tc, ok := codec.(TypedCodec); ok {
+		return t != nil && tc.Supports(t)
+	}
+	if _, ok := codec.(*jsonCodec); !ok && isProto(t) {
+		return false
+	}
+	return true
+}
+
+// offersFor 返回可用于类型 t 的内容协商候选媒体类型
+func (cs *codecs) offersFor(t reflect.Type) []string {
+	return cs.filter(cs.offers, t)
+}
+
+// docsFor 返回类型 t 写入 OpenAPI 文档的媒体类型
+func (cs *codecs) docsFor(t reflect.Type) []string {
+	return cs.filter(cs.docs, t)
+}
+
+func (cs *codecs) filter(mimes []string, t reflect.Type) []string {
+	for i, m := range mimes {
+		if !supports(cs.m[m], t) { // 仅在存在不支持的类型时才分配新切片
+			out := slices.Clone(mimes[:i])
+			for _, m = range mimes[i+1:] {
+				if supports(cs.m[m], t) {
+					out = append(out, m)
+				}
+			}
+			return out
+		}
+	}
+	return mimes
+}
+
+// RegisterCodec 注册或替换 mime 对应的编解码器。
+// 注册后该媒体类型可用于请求绑定、Params、响应内容协商，并出现在之后注册的路由的 OpenAPI 文档中。
+func (e *Engine) RegisterCodec(mime string, codec Codec) *Engine {
+	e.codecs.add(mime, codec, true)
+	return e
+}
+
+// contentType 返回响应使用的 Content-Type，文本类型附加 charset=utf-8。
+func contentType(mime string) string {
+	if strings.HasPrefix(mime, "text/") || strings.HasSuffix(mime, "+json") || strings.HasSuffix(mime, "+xml") {
+		return mime + "; charset=utf-8"
+	}
+	switch mime {
+	case MIMEJSON, MIMEXML, MIMEYAML, MIMEYAMLX, MIMETOML, MIMENDJSON:
+		return mime + "; charset=utf-8"
+	}
+	return mime
+}
+
+type xmlCodec struct{}
+
+func (xmlCodec) Decode(data []byte, v any) error {
+	return xml.Unmarshal(data, v)
+}
+
+func (xmlCodec) Encode(w io.Writer, v any) error {
+	return xml.NewEncoder(w).Encode(v)
+}
+
+type yamlCodec struct{}
+
+func (yamlCodec) Decode(data []byte, v any) error {
+	return yaml.Unmarshal(data, v)
+}
+
+func (yamlCodec) Encode(w io.Writer, v any) error {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+type tomlCodec struct{}
+
+func (tomlCodec) Decode(data []byte, v any) error {
+	return toml.Unmarshal(data, v)
+}
+
+func (tomlCodec) Encode(w io.Writer, v any) error {
+	b, err := toml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// protoCodec 使用 Protocol Buffers 二进制格式，仅支持 proto.Message。
+type protoCodec struct{}
+
+func (protoCodec) Supports(t reflect.Type) bool {
+	return t.Implements(protoMessageType) || t.Kind() != reflect.Ptr && reflect.PointerTo(t).Implements(protoMessageType)
+}
+
+func (protoCodec) Decode(data []byte, v any) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("sgin: %T does not implement proto.Message", v)
+	}
+	return proto.Unmarshal(data, m)
+}
+
+func (protoCodec) Encode(w io.Writer, v any) error {
+	m, ok := asProto(v)
+	if !ok {
+		return fmt.Errorf("sgin: %T does not implement proto.Message", v)
+	}
+
+	b, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// asProto 将 v 转换为 proto.Message，非指针的消息值会复制到新分配的指针中。
+func asProto(v any) (proto.Message, bool) {
+	if m, ok := v.(proto.Message); ok {
+		return m, true
+	}
+
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || rv.Kind() == reflect.Ptr || !reflect.PointerTo(rv.Type()).Implements(protoMessageType) {
+		return nil, false
+	}
+
+	ptr := reflect.New(rv.Type())
+	ptr.Elem().Set(rv)
+	return ptr.Interface().(proto.Message), true
+}
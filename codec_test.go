@@ -0,0 +1,86 @@
+package sgin
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type codecUser struct {
+	Name string `json:"name" form:"name" xml:"name" yaml:"name" toml:"name" binding:"required"`
+}
+
+func codecEngine() *Engine {
+	e := testEngine()
+	e.POST("/users", H(func(c *Ctx, in codecUser) (string, error) {
+		return in.Name, nil
+	}))
+	return e
+}
+
+func TestBindBody(t *testing.T) {
+	e := codecEngine()
+	for mime, body := range map[string]string{
+		MIMEJSON:  `{"name":"ann"}`,
+		MIMEXML:   `<codecUser><name>ann</name></codecUser>`,
+		MIMEYAML:  "name: ann\n",
+		MIMETOML:  "name = \"ann\"\n",
+		MIMEForm:  "name=ann",
+		MIMEYAMLX: "name: ann\n",
+	} {
+		t.Run(mime, func(t *testing.T) {
+			w := serve(e, http.MethodPost, "/users", strings.NewReader(body), HeaderContentType, mime)
+			expectStatus(t, w, http.StatusOK)
+			if w.Body.String() != `"ann"` {
+				t.Fatalf("body = %s", w.Body.String())
+			}
+		})
+	}
+
+	w := serve(e, http.MethodPost, "/users", strings.NewReader(`{}`), HeaderContentType, MIMEJSON)
+	expectStatus(t, w, http.StatusBadRequest)
+}
+
+func TestBindUnsupportedMediaType(t *testing.T) {
+	e := codecEngine()
+	w := serve(e, http.MethodPost, "/users", strings.NewReader("\x0a\x03ann"), HeaderContentType, MIMEProtobuf)
+	expectStatus(t, w, http.StatusUnsupportedMediaType)
+}
+
+// upperCodec 是测试用的自定义编解码器
+type upperCodec struct{}
+
+func (upperCodec) Decode(data []byte, v any) error { return nil }
+func (upperCodec) Encode(w io.Writer, v any) error {
+	_, err := io.WriteString(w, strings.ToUpper(v.(*codecUser).Name))
+	return err
+}
+
+func TestNegotiateResponse(t *testing.T) {
+	e := testEngine()
+	e.RegisterCodec("application/x-upper", upperCodec{})
+	e.GET("/user", H(func(c *Ctx, _ struct{}) (*codecUser, error) {
+		return &codecUser{Name: "ann"}, nil
+	}))
+
+	for accept, want := range map[string]string{
+		"":                       `{"name":"ann"}`,
+		"*/*":                    `{"name":"ann"}`,
+		MIMETextHTML + ",*/*":    `{"name":"ann"}`,
+		MIMEXML:                  `<codecUser><name>ann</name></codecUser>`,
+		MIMEYAML:                 "name: ann\n",
+		MIMETOML:                 "name = 'ann'\n",
+		"application/x-upper":    "ANN",
+		"text/plain, " + MIMEXML: `<codecUser><name>ann</name></codecUser>`,
+	} {
+		w := serve(e, http.MethodGet, "/user", nil, HeaderAccept, accept)
+		expectStatus(t, w, http.StatusOK)
+		if w.Body.String() != want {
+			t.Errorf("Accept %q: body = %q, want %q", accept, w.Body.String(), want)
+		}
+	}
+
+	// protobuf 只支持 proto.Message
+	expectStatus(t, serve(e, http.MethodGet, "/user", nil, HeaderAccept, MIMEProtobufX), http.StatusNotAcceptable)
+}
@@ -0,0 +1,326 @@
+package sgin
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Encoder 创建一个写入 w 的压缩流，level 为配置的压缩级别。
+type Encoder func(w io.Writer, level int) (io.WriteCloser, error)
+
+// CompressConfig 响应压缩配置
+type CompressConfig struct {
+	Level     int                // 压缩级别，默认 flate.DefaultCompression。
+	MinLength int                // 响应体达到该长度 (字节) 才会压缩，默认 1024。
+	MIMETypes []string           // 允许压缩的 Content-Type 前缀
+	Encoders  map[string]Encoder // 编码名称 -> 编码器，默认提供 gzip 和 deflate。
+	Prefer    []string           // 客户端权重相同时的编码优先顺序
+}
+
+// DefaultCompressConfig 返回默认的压缩配置
+func DefaultCompressConfig() CompressConfig {
+	return CompressConfig{
+		Level:     flate.DefaultCompression,
+		MinLength: 1024,
+		MIMETypes: []string{
+			"text/", MIMEJSON, MIMEXML, MIMEYAML, MIMEYAMLX, MIMETOML,
+			MIMEJavaScript, "application/problem+", MIMENDJSON,
+			"image/svg+xml",
+		},
+		Encoders: map[string]Encoder{
+			"gzip": func(w io.Writer, level int) (io.WriteCloser, error) {
+				return gzip.NewWriterLevel(w, level)
+			},
+			"deflate": func(w io.Writer, level int) (io.WriteCloser, error) {
+				return flate.NewWriter(w, level)
+			},
+		},
+		Prefer: []string{"gzip", "deflate"},
+	}
+}
+
+// Compress 返回一个根据 Accept-Encoding 协商压缩响应体的中间件
+func Compress(config ...CompressConfig) Handler {
+	cfg := DefaultCompressConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	// 权重相同时按 Prefer 顺序选择，未列出的编码排在最后。
+	offers := slices.Clone(cfg.Prefer)
+	for name := range cfg.Encoders {
+		if !slices.Contains(offers, name) {
+			offers = append(offers, name)
+		}
+	}
+	offers = slices.DeleteFunc(offers, func(name string) bool {
+		return cfg.Encoders[name] == nil
+	})
+
+	return He(func(c *Ctx) error {
+		gc := c.Gin()
+		// 无论最终是否压缩，响应都随 Accept-Encoding 变化。
+		addVary(c.Writer.Header(), HeaderAcceptEncoding)
+
+		if c.Method() == http.MethodHead || c.GetHeader(HeaderUpgrade) != "" {
+			return c.Next()
+		}
+
+		name := negotiateEncoding(c.GetHeader(HeaderAcceptEncoding), offers)
+		if name == "" {
+			return c.Next()
+		}
+
+		w := &compressWriter{
+			ResponseWriter: gc.Writer,
+			cfg:            &cfg,
+			name:           name,
+			encoder:        cfg.Encoders[name],
+		}
+
+		origin := c.Writer
+		gc.Writer, c.Writer = w, w
+		defer func() {
+			w.Close()
+			gc.Writer, c.Writer = origin, origin
+		}()
+
+		return c.Next()
+	})
+}
+
+// compressWriter 缓冲响应体的前 MinLength 个字节，再决定是否启用压缩。
+type compressWriter struct {
+	gin.ResponseWriter
+	cfg     *CompressConfig
+	name    string
+	encoder Encoder
+	buf     []byte
+	zw      io.WriteCloser // 压缩流，为 nil 时直接写入原始响应。
+	decided bool
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		if w.buf = append(w.buf, b...); len(w.buf) < w.cfg.MinLength {
+			return len(b), nil
+		}
+		if err := w.decide(false); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+
+	if w.zw != nil {
+		return w.zw.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow 在决定是否压缩之前推迟写入响应头
+func (w *compressWriter) WriteHeaderNow() {
+	if w.decided {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *compressWriter) Written() bool {
+	return len(w.buf) > 0 || w.ResponseWriter.Written()
+}
+
+// Flush 刷新压缩流和底层连接，流式响应 (如 SSE) 在首次刷新时即决定压缩。
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(true)
+	}
+	if f, ok := w.zw.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.decided = true
+	return w.ResponseWriter.Hijack()
+}
+
+// Close 写出剩余的缓冲数据并关闭压缩流
+func (w *compressWriter) Close() {
+	// 没有写入任何数据时保持原样，交由后续逻辑 (如 gin 的 404 响应) 处理。
+	if !w.decided && (len(w.buf) > 0 || w.ResponseWriter.Written()) {
+		_ = w.decide(false)
+	}
+	if w.zw != nil {
+		_ = w.zw.Close()
+	}
+}
+
+// decide 根据已缓冲的数据和响应头决定是否压缩，然后写出缓冲区。
+func (w *compressWriter) decide(stream bool) (err error) {
+	w.decided = true
+	h := w.Header()
+
+	if (stream || len(w.buf) >= w.cfg.MinLength) && w.compressible() {
+		if w.zw, err = w.encoder(w.ResponseWriter, w.cfg.Level); err != nil {
+			w.zw = nil
+			return err
+		}
+		h.Set(HeaderContentEncoding, w.name)
+		h.Del(HeaderContentLength) // 压缩后长度未知
+		if etag := h.Get(HeaderETag); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set(HeaderETag, "W/"+etag) // 压缩后的表示与原始字节不再相同
+		}
+	}
+
+	buf := w.buf
+	w.buf = nil
+
+	if w.zw == nil && !stream && h.Get(HeaderContentLength) == "" && h.Get(HeaderTransferEncoding) == "" {
+		h.Set(HeaderContentLength, strconv.Itoa(len(buf)))
+	}
+
+	w.ResponseWriter.WriteHeaderNow()
+	if len(buf) == 0 {
+		return nil
+	}
+
+	if w.zw != nil {
+		_, err = w.zw.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// compressible 检查状态码和响应头是否允许压缩
+func (w *compressWriter) compressible() bool {
+	switch status := w.Status(); {
+	case status < 200, status == http.StatusNoContent, status == http.StatusNotModified,
+		status == http.StatusPartialContent:
+		return false
+	}
+
+	h := w.Header()
+	if h.Get(HeaderContentEncoding) != "" || h.Get(HeaderContentRange) != "" ||
+		strings.Contains(h.Get(HeaderCacheControl), "no-transform") {
+		return false
+	}
+
+	ct := h.Get(HeaderContentType)
+	if ct == "" {
+		ct = http.DetectContentType(w.buf)
+	}
+
+	for _, prefix := range w.cfg.MIMETypes {
+		if strings.HasPrefix(ct, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// negotiateEncoding 根据 Accept-Encoding (含 q 权重) 从 offers 中选出最合适的编码
+func negotiateEncoding(header string, offers []string) string {
+	if header == "" || len(offers) == 0 {
+		return ""
+	}
+
+	qs := map[string]float64{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if name = strings.ToLower(strings.TrimSpace(name)); name == "" {
+			continue
+		}
+
+		q := 1.0
+		if k, v, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(k) == "q" {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				q = f
+			}
+		}
+		qs[name] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		q, ok := qs[offer]
+		if !ok {
+			if q, ok = qs["*"]; !ok {
+				continue
+			}
+		}
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best
+}
+
+// addVary 向 Vary 响应头追加 value (已存在时忽略)
+func addVary(h http.Header, value string) {
+	for _, v := range h.Values(HeaderVary) {
+		for _, field := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(field), value) {
+				return
+			}
+		}
+	}
+	h.Add(HeaderVary, value)
+}
+
+// precompressed 返回一个中间件：当客户端接受 gzip 且 fs 中存在 "<文件>.gz" 时，直接发送预压缩文件。
+func precompressed(fs http.FileSystem) Handler {
+	return func(c *gin.Context) {
+		name := c.Param("filepath")
+		if strings.HasSuffix(name, "/") || strings.HasSuffix(name, ".gz") ||
+			negotiateEncoding(c.GetHeader(HeaderAcceptEncoding), []string{"gzip"}) == "" {
+			return
+		}
+
+		origin, err := fs.Open(name)
+		if err != nil {
+			return
+		}
+		_ = origin.Close()
+
+		f, err := fs.Open(name + ".gz")
+		if err != nil {
+			return
+		}
+		defer f.Close()
+
+		stat, err := f.Stat()
+		if err != nil || stat.IsDir() {
+			return
+		}
+
+		h := c.Writer.Header()
+		addVary(h, HeaderAcceptEncoding)
+		h.Set(HeaderContentEncoding, "gzip")
+		if ct := mime.TypeByExtension(path.Ext(name)); ct != "" {
+			h.Set(HeaderContentType, ct)
+		} else {
+			h.Set(HeaderContentType, MIMEOctetStream)
+		}
+
+		c.Abort()
+		http.ServeContent(c.Writer, c.Request, name, stat.ModTime(), f)
+	}
+}
@@ -0,0 +1,76 @@
+package sgin
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	offers := []string{"gzip", "deflate"}
+	tests := []struct {
+		header, want string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"deflate", "deflate"},
+		{"br", ""},
+		{"identity", ""},
+		{"gzip;q=0.5, deflate", "deflate"},
+		{"gzip;q=0.5, deflate;q=0.8", "deflate"},
+		{"deflate, gzip", "gzip"}, // 权重相同时按 offers 顺序
+		{"gzip;q=0, deflate;q=0.1", "deflate"},
+		{"gzip;q=0", ""},
+		{"*", "gzip"},
+		{"*;q=0.5, gzip;q=0", "deflate"},
+		{"GZIP ; q=1.0", "gzip"},
+		{"gzip;q=invalid", "gzip"},
+	}
+
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header, offers); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func compressEngine() *Engine {
+	e := testEngine()
+	e.Use(Compress())
+	e.GET("/large", He(func(c *Ctx) error {
+		return c.Send(strings.Repeat("sgin ", 500))
+	}))
+	e.GET("/small", He(func(c *Ctx) error {
+		return c.Send("ok")
+	}))
+	return e
+}
+
+func TestCompress(t *testing.T) {
+	e := compressEngine()
+
+	w := serve(e, http.MethodGet, "/large", nil, HeaderAcceptEncoding, "deflate;q=0.5, gzip")
+	expectStatus(t, w, http.StatusOK)
+	if w.Header().Get(HeaderContentEncoding) != "gzip" || !strings.Contains(w.Header().Get(HeaderVary), HeaderAcceptEncoding) {
+		t.Fatalf("headers = %v", w.Header())
+	}
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := io.ReadAll(zr); !strings.Contains(string(b), "sgin sgin") {
+		t.Fatalf("decompressed body = %.40q", b)
+	}
+
+	w = serve(e, http.MethodGet, "/large", nil, HeaderAcceptEncoding, "gzip;q=0")
+	if w.Header().Get(HeaderContentEncoding) != "" {
+		t.Fatal("gzip;q=0 must not be compressed")
+	}
+
+	w = serve(e, http.MethodGet, "/small", nil, HeaderAcceptEncoding, "gzip")
+	if w.Header().Get(HeaderContentEncoding) != "" || w.Header().Get(HeaderContentLength) != "4" {
+		t.Fatalf("small response headers = %v", w.Header())
+	}
+}
@@ -0,0 +1,197 @@
+package sgin
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/baagod/sgin/v2/helper"
+)
+
+// bindingFormats 是 validator 规则对应的 JSON Schema 格式
+var bindingFormats = map[string]string{
+	"email":            "email",
+	"url":              "uri",
+	"http_url":         "uri",
+	"uri":              "uri",
+	"uuid":             "uuid",
+	"uuid3":            "uuid",
+	"uuid4":            "uuid",
+	"uuid5":            "uuid",
+	"uuid_rfc4122":     "uuid",
+	"uuid3_rfc4122":    "uuid",
+	"uuid4_rfc4122":    "uuid",
+	"uuid5_rfc4122":    "uuid",
+	"ipv4":             "ipv4",
+	"ipv6":             "ipv6",
+	"hostname":         "hostname",
+	"hostname_rfc1123": "hostname",
+	"fqdn":             "hostname",
+}
+
+// bindingPatterns 是 validator 规则对应的正则表达式
+var bindingPatterns = map[string]string{
+	"alpha":       "^[a-zA-Z]+$",
+	"alphanum":    "^[a-zA-Z0-9]+$",
+	"numeric":     `^[-+]?[0-9]+(?:\.[0-9]+)?$`,
+	"number":      "^[0-9]+$",
+	"hexadecimal": "^(0[xX])?[0-9a-fA-F]+$",
+	"lowercase":   "^[^A-Z]*$",
+	"uppercase":   "^[^a-z]*$",
+}
+
+// bindingRequired 报告 binding 标签是否要求字段必填 (dive 之后的规则作用于元素，不影响字段本身)。
+func bindingRequired(tag string) bool {
+	for _, rule := range strings.Split(tag, ",") {
+		if rule == "dive" {
+			break
+		}
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// applyBinding 将 validator 的 binding 规则转换为 Schema 的约束，t 为字段的 Go 类型。
+// 规则根据类型作用于数值 (minimum)、字符串 (minLength) 或数组 (minItems)，
+// dive 之后的规则作用于数组元素或 map 的值，无法表示的规则 (如 required_if、a|b) 被忽略。
+func (r *Registry) applyBinding(s *Schema, t reflect.Type, rules []string, field string) {
+	if s == nil {
+		return
+	}
+
+	t = helper.Deref(t)
+	for i, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		if strings.Contains(name, "|") {
+			continue
+		}
+
+		switch name {
+		case "dive":
+			switch t.Kind() {
+			case reflect.Slice, reflect.Array:
+				r.applyBinding(s.Items, t.Elem(), rules[i+1:], field)
+			case reflect.Map:
+				elem, _ := s.AdditionalProperties.(*Schema)
+				r.applyBinding(elem, t.Elem(), rules[i+1:], field)
+			}
+			return
+		case "min", "gte":
+			s.setBound(t, param, false, false)
+		case "max", "lte":
+			s.setBound(t, param, true, false)
+		case "gt":
+			s.setBound(t, param, false, true)
+		case "lt":
+			s.setBound(t, param, true, true)
+		case "len":
+			s.setBound(t, param, false, false)
+			s.setBound(t, param, true, false)
+		case "oneof":
+			var enum []any
+			for _, v := range splitOneOf(param) {
+				enum = append(enum, r.DecodeJSON(v, field, s))
+			}
+			s.Enum = enum
+		case "unique":
+			if s.Type == TypeArray {
+				s.UniqueItems = true
+			}
+		case "datetime":
+			switch param {
+			case "2006-01-02":
+				s.Format = "date"
+			case "15:04:05":
+				s.Format = "time"
+			default:
+				s.Format = "date-time"
+			}
+		case "startswith":
+			s.setPattern("^" + regexp.QuoteMeta(param))
+		case "endswith":
+			s.setPattern(regexp.QuoteMeta(param) + "$")
+		case "contains":
+			s.setPattern(regexp.QuoteMeta(param))
+		default:
+			if format, ok := bindingFormats[name]; ok {
+				s.Format = format
+			} else if pattern, ok := bindingPatterns[name]; ok {
+				s.setPattern(pattern)
+			}
+		}
+	}
+}
+
+// setBound 根据类型 t 设置最小值 (max 为 true 时设置最大值)，exclusive 表示不包含边界。
+func (s *Schema) setBound(t reflect.Type, param string, max, exclusive bool) {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(param, 64)
+		if err != nil { // 如 time.Duration 的 min=1s
+			return
+		}
+		switch {
+		case max && exclusive:
+			s.ExclusiveMaximum = &n
+		case max:
+			s.Maximum = &n
+		case exclusive:
+			s.ExclusiveMinimum = &n
+		default:
+			s.Minimum = &n
+		}
+	case reflect.String, reflect.Slice, reflect.Array:
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			return
+		}
+		if exclusive { // 长度为整数，gt=2 即 minLength=3。
+			if max {
+				n--
+			} else {
+				n++
+			}
+		}
+
+		switch {
+		case t.Kind() == reflect.String:
+			if max {
+				s.MaxLength = &n
+			} else {
+				s.MinLength = &n
+			}
+		case s.Type == TypeArray: // []byte 在文档中是 base64 字符串，长度与字节数不同，不设置约束。
+			if max {
+				s.MaxItems = &n
+			} else {
+				s.MinItems = &n
+			}
+		}
+	}
+}
+
+// setPattern 设置正则约束，JSON Schema 只支持一个 pattern，已存在时保留第一个。
+func (s *Schema) setPattern(pattern string) {
+	if s.Pattern == "" {
+		s.Pattern = pattern
+	}
+}
+
+// splitOneOf 以空格分隔 oneof 的参数，支持单引号包裹含空格的值。
+func splitOneOf(param string) (values []string) {
+	for _, m := range oneOfRegex.FindAllStringSubmatch(param, -1) {
+		if m[1] != "" {
+			values = append(values, m[1])
+		} else {
+			values = append(values, m[2])
+		}
+	}
+	return
+}
+
+var oneOfRegex = regexp.MustCompile(`'([^']*)'|(\S+)`)
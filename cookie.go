@@ -0,0 +1,247 @@
+package sgin
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+var (
+	ErrCookieInvalid = errors.New("sgin: secure cookie is invalid or has been tampered with")
+	ErrCookieExpired = errors.New("sgin: secure cookie has expired")
+)
+
+// maxCookieSize 单个 Cookie 的最大长度 (浏览器通常限制为 4KB)
+const maxCookieSize = 4096
+
+// CookieConfig 定义签名/加密 Cookie 的密钥和默认属性
+type CookieConfig struct {
+	// Keys 为密钥环：Keys[0] 用于签名和加密，其余密钥仅用于验证旧 Cookie，以便平滑轮换。
+	// 每个密钥建议至少 32 字节的随机数据。
+	Keys [][]byte
+
+	Encrypt  bool          // 是否使用 AES-GCM 加密值 (默认仅签名，值对客户端可见)
+	MaxAge   int           // 默认有效期 (秒)，写入载荷并在读取时校验，<= 0 表示会话 Cookie。
+	Path     string        // 默认 "/"
+	Domain   string        // 默认为空
+	Secure   bool          // 仅通过 HTTPS 发送
+	HttpOnly bool          // 禁止 JavaScript 访问，默认 true。
+	SameSite http.SameSite // 默认 http.SameSiteLaxMode
+}
+
+// DefaultCookieConfig 返回默认的安全 Cookie 配置
+func DefaultCookieConfig() CookieConfig {
+	return CookieConfig{
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// cookieKey 是由密钥派生的签名密钥和加密算法
+type cookieKey struct {
+	hash []byte
+	aead cipher.AEAD
+}
+
+// secureCookies 负责 Cookie 值的编码和解码
+type secureCookies struct {
+	cfg  CookieConfig
+	keys []cookieKey
+}
+
+func newSecureCookies(cfg CookieConfig) (*secureCookies, error) {
+	if len(cfg.Keys) == 0 {
+		return nil, errors.New("sgin: CookieConfig.Keys is empty")
+	}
+
+	sc := &secureCookies{cfg: cfg}
+	for _, key := range cfg.Keys {
+		if len(key) == 0 {
+			return nil, errors.New("sgin: CookieConfig.Keys contains an empty key")
+		}
+
+		// 分别派生签名密钥和 AES-256 密钥，避免同一密钥用于不同用途。
+		block, err := aes.NewCipher(deriveKey(key, "sgin-cookie-encrypt"))
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		sc.keys = append(sc.keys, cookieKey{hash: deriveKey(key, "sgin-cookie-sign"), aead: aead})
+	}
+
+	return sc, nil
+}
+
+func deriveKey(secret []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// encode 生成 Cookie 值：base64url(body || HMAC-SHA256(name || body))，
+// body 为 [8 字节过期时间戳 || 值]，加密时为 [nonce || AES-GCM 密文]。
+func (sc *secureCookies) encode(name string, value []byte, maxAge int) (string, error) {
+	key := sc.keys[0]
+
+	var expires int64
+	if maxAge > 0 {
+		expires = time.Now().Add(time.Duration(maxAge) * time.Second).Unix()
+	}
+
+	body := binary.BigEndian.AppendUint64(make([]byte, 0, 8+len(value)), uint64(expires))
+	body = append(body, value...)
+
+	if sc.cfg.Encrypt {
+		nonce := make([]byte, key.aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+		body = key.aead.Seal(nonce, nonce, body, []byte(name)) // Cookie 名称作为附加数据，防止值被挪用。
+	}
+
+	mac := hmac.New(sha256.New, key.hash)
+	mac.Write([]byte(name))
+	mac.Write(body)
+
+	encoded := base64.RawURLEncoding.EncodeToString(mac.Sum(body))
+	if len(name)+len(encoded) > maxCookieSize {
+		return "", fmt.Errorf("sgin: secure cookie %q exceeds %d bytes", name, maxCookieSize)
+	}
+
+	return encoded, nil
+}
+
+// decode 依次使用密钥环中的密钥验证并解码 Cookie 值
+func (sc *secureCookies) decode(name, value string) ([]byte, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) < sha256.Size {
+		return nil, ErrCookieInvalid
+	}
+
+	body, sum := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+	for _, key := range sc.keys {
+		mac := hmac.New(sha256.New, key.hash)
+		mac.Write([]byte(name))
+		mac.Write(body)
+		if !hmac.Equal(mac.Sum(nil), sum) {
+			continue
+		}
+
+		if sc.cfg.Encrypt {
+			size := key.aead.NonceSize()
+			if len(body) < size {
+				return nil, ErrCookieInvalid
+			}
+			if body, err = key.aead.Open(nil, body[:size], body[size:], []byte(name)); err != nil {
+				return nil, ErrCookieInvalid
+			}
+		}
+
+		if len(body) < 8 {
+			return nil, ErrCookieInvalid
+		}
+		if expires := int64(binary.BigEndian.Uint64(body)); expires > 0 && time.Now().Unix() >= expires {
+			return nil, ErrCookieExpired
+		}
+
+		return body[8:], nil
+	}
+
+	return nil, ErrCookieInvalid
+}
+
+// SetSecureCookie 设置一个签名 (启用 Encrypt 时同时加密) 的 Cookie。
+// value 为 string 或 []byte 时原样保存，其他类型编码为 JSON。maxAge 默认为 CookieConfig.MaxAge。
+func (c *Ctx) SetSecureCookie(name string, value any, maxAge ...int) error {
+	sc, err := c.engine.secureCookies()
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch v := value.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		if data, err = c.engine.json.Marshal(v); err != nil {
+			return err
+		}
+	}
+
+	age := sc.cfg.MaxAge
+	if len(maxAge) > 0 {
+		age = maxAge[0]
+	}
+
+	encoded, err := sc.encode(name, data, age)
+	if err != nil {
+		return err
+	}
+
+	c.SetCookie(name, encoded, sc.cfg.Path, sc.cfg.Domain, age, sc.cfg.Secure, sc.cfg.HttpOnly, sc.cfg.SameSite)
+	return nil
+}
+
+// SecureCookie 读取并验证由 SetSecureCookie 设置的 Cookie。
+// 不存在时返回 http.ErrNoCookie，签名无效返回 ErrCookieInvalid，已过期返回 ErrCookieExpired。
+func (c *Ctx) SecureCookie(name string) (string, error) {
+	data, err := c.secureCookie(name)
+	return string(data), err
+}
+
+func (c *Ctx) secureCookie(name string) ([]byte, error) {
+	sc, err := c.engine.secureCookies()
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := c.Cookie(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return sc.decode(name, value)
+}
+
+// SecureCookie 读取安全 Cookie 并解码为 T (string 原样返回，其他类型按 JSON 解码)。
+func SecureCookie[T any](c *Ctx, name string) (v T, err error) {
+	data, err := c.secureCookie(name)
+	if err != nil {
+		return v, err
+	}
+
+	if p, ok := any(&v).(*string); ok {
+		*p = string(data)
+		return v, nil
+	}
+	if reflect.TypeFor[T]() == reflect.TypeFor[[]byte]() {
+		return any(data).(T), nil
+	}
+
+	err = c.engine.json.Decode(data, &v)
+	return v, err
+}
+
+// secureCookies 返回引擎的安全 Cookie 编解码器，未配置密钥时返回错误。
+func (e *Engine) secureCookies() (*secureCookies, error) {
+	if e.cookies == nil {
+		return nil, errors.New("sgin: secure cookies require Config.Cookie with at least one key")
+	}
+	return e.cookies, nil
+}
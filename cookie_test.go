@@ -0,0 +1,157 @@
+package sgin
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	oldCookieKey = []byte("0123456789abcdef0123456789abcdef")
+	newCookieKey = []byte("fedcba9876543210fedcba9876543210")
+)
+
+func mustSecureCookies(t *testing.T, encrypt bool, keys ...[]byte) *secureCookies {
+	t.Helper()
+	sc, err := newSecureCookies(CookieConfig{Keys: keys, Encrypt: encrypt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sc
+}
+
+func TestSecureCookieTamper(t *testing.T) {
+	for _, encrypt := range []bool{false, true} {
+		sc := mustSecureCookies(t, encrypt, newCookieKey)
+		value, err := sc.encode("uid", []byte("42"), 60)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if b, err := sc.decode("uid", value); err != nil || string(b) != "42" {
+			t.Fatalf("encrypt=%v: decode = %q, %v", encrypt, b, err)
+		}
+		if encrypt == strings.Contains(string(mustBase64(t, value)), "42") {
+			t.Fatalf("encrypt=%v: value visibility is wrong", encrypt)
+		}
+
+		raw := mustBase64(t, value)
+		for i := range raw {
+			tampered := append([]byte(nil), raw...)
+			tampered[i] ^= 0x01
+			if _, err := sc.decode("uid", base64.RawURLEncoding.EncodeToString(tampered)); !errors.Is(err, ErrCookieInvalid) {
+				t.Fatalf("encrypt=%v: byte %d tampered, err = %v", encrypt, i, err)
+			}
+		}
+
+		for _, bad := range []string{"", "!!!", value[:10], value + "A"} {
+			if _, err := sc.decode("uid", bad); !errors.Is(err, ErrCookieInvalid) {
+				t.Fatalf("encrypt=%v: decode(%q) err = %v", encrypt, bad, err)
+			}
+		}
+		if _, err := sc.decode("admin", value); !errors.Is(err, ErrCookieInvalid) {
+			t.Fatalf("encrypt=%v: value moved to another cookie, err = %v", encrypt, err)
+		}
+	}
+}
+
+func TestSecureCookieKeyRotation(t *testing.T) {
+	for _, encrypt := range []bool{false, true} {
+		old := mustSecureCookies(t, encrypt, oldCookieKey)
+		rotated := mustSecureCookies(t, encrypt, newCookieKey, oldCookieKey)
+		fresh := mustSecureCookies(t, encrypt, newCookieKey)
+
+		value, _ := old.encode("uid", []byte("42"), 0)
+		if b, err := rotated.decode("uid", value); err != nil || string(b) != "42" {
+			t.Fatalf("encrypt=%v: old cookie after rotation = %q, %v", encrypt, b, err)
+		}
+		if _, err := fresh.decode("uid", value); !errors.Is(err, ErrCookieInvalid) {
+			t.Fatalf("encrypt=%v: retired key still accepted, err = %v", encrypt, err)
+		}
+
+		// 轮换后签发的 Cookie 使用新密钥
+		value, _ = rotated.encode("uid", []byte("43"), 0)
+		if b, err := fresh.decode("uid", value); err != nil || string(b) != "43" {
+			t.Fatalf("encrypt=%v: new cookie = %q, %v", encrypt, b, err)
+		}
+		if _, err := old.decode("uid", value); !errors.Is(err, ErrCookieInvalid) {
+			t.Fatalf("encrypt=%v: new cookie accepted by the old key, err = %v", encrypt, err)
+		}
+	}
+}
+
+func TestSecureCookieExpired(t *testing.T) {
+	sc := mustSecureCookies(t, false, newCookieKey)
+
+	// 手动签发一个已过期的 Cookie，客户端无法通过修改 Max-Age 延长有效期。
+	body := binary.BigEndian.AppendUint64(nil, uint64(time.Now().Add(-time.Minute).Unix()))
+	body = append(body, "42"...)
+	mac := hmac.New(sha256.New, sc.keys[0].hash)
+	mac.Write([]byte("uid"))
+	mac.Write(body)
+	value := base64.RawURLEncoding.EncodeToString(mac.Sum(body))
+
+	if _, err := sc.decode("uid", value); !errors.Is(err, ErrCookieExpired) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestSecureCookieHandlers(t *testing.T) {
+	type profile struct {
+		ID int `json:"id"`
+	}
+
+	e := testEngine(Config{Cookie: func(c *CookieConfig) {
+		c.Keys, c.Encrypt, c.MaxAge = [][]byte{newCookieKey}, true, 60
+	}})
+	e.POST("/login", He(func(c *Ctx) error {
+		return c.SetSecureCookie("profile", profile{ID: 7})
+	}))
+	e.GET("/me", He(func(c *Ctx) error {
+		p, err := SecureCookie[profile](c, "profile")
+		if err != nil {
+			return ErrUnauthorized(err.Error())
+		}
+		return c.Send(p.ID)
+	}))
+
+	w := serve(e, http.MethodPost, "/login", nil)
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].MaxAge != 60 || !cookies[0].HttpOnly {
+		t.Fatalf("cookies = %v", cookies)
+	}
+
+	w = serve(e, http.MethodGet, "/me", nil, "Cookie", "profile="+cookies[0].Value)
+	expectStatus(t, w, http.StatusOK)
+	if w.Body.String() != "7" {
+		t.Fatalf("body = %s", w.Body.String())
+	}
+
+	expectStatus(t, serve(e, http.MethodGet, "/me", nil), http.StatusUnauthorized)
+	expectStatus(t, serve(e, http.MethodGet, "/me", nil, "Cookie", "profile=x"+cookies[0].Value), http.StatusUnauthorized)
+
+	// 未配置密钥时返回错误
+	e = testEngine()
+	e.GET("/", He(func(c *Ctx) error {
+		if err := c.SetSecureCookie("a", "b"); err == nil {
+			t.Error("SetSecureCookie without keys succeeded")
+		}
+		return nil
+	}))
+	serve(e, http.MethodGet, "/", nil)
+}
+
+func mustBase64(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -0,0 +1,266 @@
+package sgin
+
+import (
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"iter"
+	"net/url"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/baagod/sgin/v2/helper"
+)
+
+var textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+
+// csvColumn 描述 CSV 的一列
+type csvColumn struct {
+	name  string
+	index []int
+}
+
+// isCSVRow 检查 t 是否可以作为 CSV 的一行 (结构体或结构体指针)
+func isCSVRow(t reflect.Type) bool {
+	t = helper.Deref(t)
+	return t.Kind() == reflect.Struct && t != timeType && t != urlType
+}
+
+// csvElem 返回切片、数组或流式类型中可作为 CSV 行的元素类型
+func csvElem(t reflect.Type) (reflect.Type, bool) {
+	t = helper.Deref(t)
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		if t.Elem().Kind() != reflect.Uint8 && isCSVRow(t.Elem()) {
+			return t.Elem(), true
+		}
+		return nil, false
+	}
+	if elem, ok := streamElem(t); ok && isCSVRow(elem) {
+		return elem, true
+	}
+	return nil, false
+}
+
+// csvColumns 按字段声明顺序 (内嵌结构体就地展开) 生成表头，名称取自 csv 或 json 标签，值为 "-" 的字段会被忽略。
+func csvColumns(t reflect.Type) (cols []csvColumn) {
+	root := helper.Deref(t)
+
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := range t.NumField() {
+			f := t.Field(i)
+			idx := append(slices.Clone(index), i)
+
+			if f.Anonymous && f.Tag.Get("csv") == "" && f.Tag.Get("json") == "" {
+				if et := helper.Deref(f.Type); et.Kind() == reflect.Struct {
+					walk(et, idx)
+					continue
+				}
+			}
+
+			if !f.IsExported() {
+				continue
+			}
+			// 被外层同名字段遮蔽的内嵌字段不输出
+			if sf, ok := root.FieldByName(f.Name); !ok || !slices.Equal(sf.Index, idx) {
+				continue
+			}
+
+			name := f.Name
+			if tag, ok := f.Tag.Lookup("csv"); ok {
+				name = strings.Split(tag, ",")[0]
+			} else if n := strings.Split(f.Tag.Get("json"), ",")[0]; n != "" {
+				name = n
+			}
+			if name == "-" {
+				continue
+			}
+
+			cols = append(cols, csvColumn{name: name, index: idx})
+		}
+	}
+
+	walk(root, nil)
+	return cols
+}
+
+// wantCSV 检查是否应以 CSV 格式响应 body：?format=csv 或 Accept 协商为 text/csv。
+func (c *Ctx) wantCSV(body any) bool {
+	if _, ok := csvElem(reflect.TypeOf(body)); !ok {
+		return false
+	}
+	if c.ctx.Query("format") == "csv" {
+		return true
+	}
+	if c.GetHeader(HeaderAccept) == "" {
+		return false
+	}
+	offers := append(append([]string{}, c.engine.codecs.offers...), MIMECSV)
+	return c.ctx.NegotiateFormat(offers...) == MIMECSV
+}
+
+// sendCSV 以 CSV 格式流式写出 body 中的每一行
+func (c *Ctx) sendCSV(body any) {
+	gc := c.ctx
+	gc.Abort()
+
+	elem, _ := csvElem(reflect.TypeOf(body))
+	cols := csvColumns(elem)
+
+	var rows iter.Seq2[any, error]
+	live := false
+
+	if seq, isLive, ok := asStream(body, c.Request.Context().Done()); ok {
+		rows, live = seq, isLive
+	} else {
+		v := reflect.Indirect(reflect.ValueOf(body))
+		rows = func(yield func(any, error) bool) {
+			for i := range v.Len() {
+				if !yield(v.Index(i).Interface(), nil) {
+					return
+				}
+			}
+		}
+	}
+
+	h := c.Writer.Header()
+	h.Set(HeaderContentType, MIMECSV+"; charset=utf-8")
+	if h.Get(HeaderContentDisposition) == "" {
+		h.Set(HeaderContentDisposition, `attachment; filename*=UTF-8''`+url.PathEscape(c.csvFilename()))
+	}
+
+	w := csv.NewWriter(c.Writer)
+	record := make([]string, len(cols))
+	started := false
+	lastFlush := time.Now()
+
+	start := func() {
+		if !started {
+			started = true
+			c.Writer.WriteHeader(c.StatusCode())
+			for i, col := range cols {
+				record[i] = col.name
+			}
+			_ = w.Write(record)
+		}
+	}
+
+	for row, err := range rows {
+		if err != nil {
+			if !started {
+				h.Del(HeaderContentDisposition)
+				h.Del(HeaderContentType)
+				_ = c.engine.cfg.ErrorHandler(c, err)
+				return
+			}
+			_ = gc.Error(err) // 由 Logger 记录
+			break
+		}
+
+		start()
+		rv := reflect.ValueOf(row)
+		for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+			rv = rv.Elem()
+		}
+		if rv.Kind() != reflect.Struct {
+			continue // nil 指针
+		}
+
+		for i, col := range cols {
+			fv, err := rv.FieldByIndexErr(col.index)
+			if err != nil {
+				record[i] = "" // 内嵌的 nil 指针
+				continue
+			}
+			record[i] = csvValue(fv, c.engine.json)
+		}
+
+		if err = w.Write(record); err != nil {
+			return // 客户端已断开
+		}
+		if live || time.Since(lastFlush) >= streamFlushInterval {
+			w.Flush()
+			c.Writer.Flush()
+			lastFlush = time.Now()
+		}
+	}
+
+	start()
+	w.Flush()
+	c.Writer.Flush()
+}
+
+// csvFilename 根据路由生成下载文件名，例如 "/users/:id/orders" -> "orders.csv"。
+func (c *Ctx) csvFilename() string {
+	route := c.Path(true)
+	if route == "" {
+		route = c.Path()
+	}
+
+	segments := strings.Split(strings.Trim(route, "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if s := segments[i]; s != "" && s[0] != ':' && s[0] != '*' {
+			return path.Base(s) + ".csv"
+		}
+	}
+
+	return "export.csv"
+}
+
+// csvValue 将字段值格式化为 CSV 单元格文本
+func csvValue(v reflect.Value, json *jsonCodec) string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		if t := v.Interface().(time.Time); !t.IsZero() {
+			return t.Format(time.RFC3339)
+		}
+		return ""
+	}
+
+	if v.Type().Implements(textMarshalerType) {
+		if b, err := v.Interface().(encoding.TextMarshaler).MarshalText(); err == nil {
+			return csvText(string(b))
+		}
+	}
+
+	switch v.Kind() { // 数值和布尔值不会被解析为公式，负数保持原样。
+	case reflect.String:
+		return csvText(v.String())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits())
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		if (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.IsNil() {
+			return ""
+		}
+		if b, err := json.Marshal(v.Interface()); err == nil {
+			return csvText(string(b)) // 复杂类型以 JSON 文本表示
+		}
+	}
+
+	return csvText(fmt.Sprint(v.Interface()))
+}
+
+// csvText 在以 =、+、-、@、制表符或回车开头的文本前添加单引号，
+// 防止电子表格 (如 Excel) 将单元格解析为公式 (CSV 注入)。
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
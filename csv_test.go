@@ -0,0 +1,104 @@
+package sgin
+
+import (
+	"encoding/csv"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type csvBase struct {
+	ID int `json:"id"`
+}
+
+type csvUser struct {
+	csvBase
+	Name    string     `json:"name"`
+	Email   string     `csv:"email_addr" json:"email"`
+	Balance float64    `json:"balance"`
+	Secret  string     `csv:"-"`
+	Created time.Time  `json:"created"`
+	Tags    []string   `json:"tags"`
+	Parent  *csvParent `json:"parent"`
+}
+
+type csvParent struct {
+	Name string `json:"name"`
+}
+
+func csvEngine(users []csvUser) *Engine {
+	e := testEngine()
+	e.GET("/teams/:id/users", Ho(func(c *Ctx, _ struct{}) []csvUser {
+		return users
+	}))
+	return e
+}
+
+func readCSV(t *testing.T, body string) [][]string {
+	t.Helper()
+	records, err := csv.NewReader(strings.NewReader(body)).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v\n%s", err, body)
+	}
+	return records
+}
+
+func TestCSVExport(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	e := csvEngine([]csvUser{
+		{csvBase{1}, "Ann", "ann@example.com", -12.5, "s", created, []string{"a", "b"}, &csvParent{"Bob"}},
+		{csvBase{2}, "Smith, \"J\"\nJr.", "", 0, "", time.Time{}, nil, nil},
+	})
+
+	w := serve(e, http.MethodGet, "/teams/1/users?format=csv", nil)
+	expectStatus(t, w, http.StatusOK)
+	if ct := w.Header().Get(HeaderContentType); ct != MIMECSV+"; charset=utf-8" {
+		t.Fatalf("content type = %s", ct)
+	}
+	if cd := w.Header().Get(HeaderContentDisposition); !strings.Contains(cd, "users.csv") {
+		t.Fatalf("content disposition = %s", cd)
+	}
+
+	want := [][]string{
+		{"id", "name", "email_addr", "balance", "created", "tags", "parent"},
+		{"1", "Ann", "ann@example.com", "-12.5", "2024-01-02T03:04:05Z", `["a","b"]`, `{"name":"Bob"}`},
+		{"2", "Smith, \"J\"\nJr.", "", "0", "", "", ""},
+	}
+	if got := readCSV(t, w.Body.String()); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("records = %q, want %q", got, want)
+	}
+
+	// Accept 协商，空列表只输出表头。
+	e = csvEngine(nil)
+	w = serve(e, http.MethodGet, "/teams/1/users", nil, HeaderAccept, MIMECSV)
+	if got := readCSV(t, w.Body.String()); len(got) != 1 || got[0][0] != "id" {
+		t.Fatalf("records = %q", got)
+	}
+
+	// 未请求 CSV 时使用 JSON
+	w = serve(e, http.MethodGet, "/teams/1/users", nil, HeaderAccept, MIMEJSON)
+	if !strings.HasPrefix(w.Header().Get(HeaderContentType), MIMEJSON) {
+		t.Fatalf("content type = %s", w.Header().Get(HeaderContentType))
+	}
+}
+
+func TestCSVFormulaInjection(t *testing.T) {
+	var users []csvUser
+	for _, name := range []string{"=cmd|' /C calc'!A0", "+1", "-1+2", "@SUM(A1)", "\tx", "\rx", "a=b"} {
+		users = append(users, csvUser{Name: name, Balance: -3})
+	}
+
+	w := serve(csvEngine(users), http.MethodGet, "/teams/1/users?format=csv", nil)
+	records := readCSV(t, w.Body.String())[1:]
+
+	for i, want := range []string{"'=cmd|' /C calc'!A0", "'+1", "'-1+2", "'@SUM(A1)", "'\tx", "'\rx", "a=b"} {
+		if records[i][1] != want {
+			t.Errorf("row %d: name = %q, want %q", i, records[i][1], want)
+		}
+		if records[i][3] != "-3" {
+			t.Errorf("row %d: number = %q", i, records[i][3])
+		}
+	}
+}
@@ -1,17 +1,17 @@
 package sgin
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"time"
 
-	"github.com/bytedance/sonic"
 	"github.com/clbanning/mxj/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/xid"
@@ -67,14 +67,17 @@ func (c *Ctx) Params() map[string]any {
 	ct := c.GetHeader(HeaderContentType)
 
 	if strings.HasPrefix(ct, MIMEJSON) {
-		r := bytes.NewReader(c.RawBody())
-		dec := sonic.ConfigDefault.NewDecoder(r)
-		dec.UseNumber()
-		_ = dec.Decode(&c.cache)
+		if body := c.RawBody(); len(body) > 0 {
+			_ = c.engine.json.decode(body, &c.cache, true) // 数字保留为 json.Number
+		}
 	} else if strings.HasPrefix(ct, MIMEXML) || strings.HasPrefix(ct, MIMETextXML) {
 		if m, _ := mxj.NewMapXml(c.RawBody()); m != nil {
 			c.cache = m
 		}
+	} else if codec := c.engine.codecs.get(ct); codec != nil && supports(codec, reflect.TypeOf(c.cache)) {
+		if body := c.RawBody(); len(body) > 0 {
+			_ = codec.Decode(body, &c.cache)
+		}
 	}
 
 	partForm, err := c.ctx.MultipartForm()
@@ -245,6 +248,11 @@ func (c *Ctx) GetHeader(key string, value ...string) string {
 	return header
 }
 
+// LastEventID 返回 SSE 客户端重连时携带的 Last-Event-ID，用于从断点恢复事件流。
+func (c *Ctx) LastEventID() string {
+	return c.GetHeader(HeaderLastEventID)
+}
+
 func (c *Ctx) StatusCode() int {
 	return c.ctx.Writer.Status()
 }
@@ -278,8 +286,7 @@ func (c *Ctx) Send(body any) error {
 }
 
 func (c *Ctx) SendJSON(data any) error {
-	c.ctx.Abort()
-	c.ctx.JSON(c.StatusCode(), data)
+	c.encode(contentType(MIMEJSON), c.engine.json, data)
 	return nil
 }
 
@@ -450,7 +457,11 @@ func (c *Ctx) send(data any, err error) {
 	}
 
 	if data != nil {
-		_ = c.Send(data) // 发送数据
+		c.writePageLinks(data)
+		if sel, ok := c.Get(fieldsKey).(*fieldSelection); ok {
+			data = selectFields(data, sel) // 只保留 fields 查询参数选择的字段
+		}
+		_ = c.Send(c.envelope(data)) // 发送数据
 	}
 }
 
@@ -467,18 +478,68 @@ func (c *Ctx) autoFormat(body any) {
 		return
 	}
 
+	// 结构体列表可以导出为 CSV (?format=csv 或 Accept: text/csv)
+	if c.wantCSV(body) {
+		c.sendCSV(body)
+		return
+	}
+
+	// 迭代器和通道以流的形式发送
+	if seq, live, ok := asStream(body, c.Request.Context().Done()); ok {
+		c.sendStream(seq, live)
+		return
+	}
+
+	// 已通过 Content() 指定响应类型时，直接使用对应的编解码器或发送纯文本。
+	if ct := c.Writer.Header().Get(HeaderContentType); ct != "" {
+		if codec := c.engine.codecs.get(ct); codec != nil && supports(codec, reflect.TypeOf(body)) {
+			c.encode(ct, codec, body)
+			return
+		}
+		if strings.HasPrefix(ct, MIMETextPlain) {
+			_ = c.SendText(body)
+			return
+		}
+	}
+
 	// Accept 前缀是 "text/html" 为浏览器直接访问，直接返回 JSON。
-	if strings.HasPrefix(c.GetHeader(HeaderAccept), MIMETextHTML) {
-		_ = c.SendJSON(body)
+	mime := MIMEJSON
+	if !strings.HasPrefix(c.GetHeader(HeaderAccept), MIMETextHTML) {
+		// 其他情况，在支持 body 类型的已注册编解码器中进行内容协商。
+		if mime = gc.NegotiateFormat(c.engine.codecs.offersFor(reflect.TypeOf(body))...); mime == "" {
+			_ = gc.AbortWithError(http.StatusNotAcceptable, errors.New("the accepted formats are not offered by the server"))
+			return
+		}
+	}
+
+	c.encode(contentType(mime), c.engine.codecs.get(mime), body)
+}
+
+// encode 使用 codec 编码 body 并写入响应
+func (c *Ctx) encode(ct string, codec Codec, body any) {
+	c.ctx.Abort()
+	if c.Writer.Header().Get(HeaderContentType) == "" {
+		c.Content(ct)
+	}
+
+	c.Writer.WriteHeader(c.StatusCode())
+	if !bodyAllowed(c.StatusCode()) {
+		c.Writer.WriteHeaderNow()
 		return
 	}
 
-	// 其他情况，使用 Gin Negotiate 进行正常内容协商。
-	gc.Negotiate(c.StatusCode(), gin.Negotiate{
-		Offered: []string{
-			MIMEJSON, MIMETextHTML, MIMEXML,
-			MIMETextXML, MIMEYAML, MIMEYAMLX, MIMETOML,
-		},
-		Data: body,
-	})
+	if err := codec.Encode(c.Writer, body); err != nil {
+		_ = c.ctx.Error(err)
+	}
+}
+
+// bodyAllowed 报告给定的状态码是否允许携带响应体
+func bodyAllowed(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == http.StatusNoContent, status == http.StatusNotModified:
+		return false
+	}
+	return true
 }
@@ -0,0 +1,207 @@
+package sgin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 渲染器的静态资源版本记录在 ui/assets.json 中，执行 go generate 下载到 ui 目录后随包内嵌，
+// 只提供能够随包内嵌 (包括许可证) 的渲染器，文档页面不会访问外网。
+//go:generate go run ./internal/docsassets
+
+//go:embed ui
+var uiFS embed.FS
+
+// DocsRenderer 是文档页面使用的渲染器
+type DocsRenderer string
+
+const (
+	DocsSwaggerUI DocsRenderer = "swagger-ui" // Swagger UI
+)
+
+// DocsConfig 定义文档页面和规范的路由配置
+type DocsConfig struct {
+	Renderer   DocsRenderer // 渲染器，默认 DocsSwaggerUI。
+	Title      string       // 页面标题
+	Path       string       // 文档页面的路径，默认 "/docs"，为空时不提供页面。
+	YAMLPath   string       // YAML 格式规范的路径，默认 "/openapi.yaml"，为空时不提供。
+	JSONPath   string       // JSON 格式规范的路径，默认 "/openapi.json"，为空时不提供。
+	Release    bool         // 在 release 模式下也提供文档，通常与 Middleware 一起使用。
+	Middleware []Handler    // 文档路由的中间件，如身份认证。
+	Assets     fs.FS        // 渲染器的静态资源，默认使用内嵌的资源，缺少文件时 New 会 panic。
+}
+
+// DefaultDocsConfig 返回默认的文档配置
+func DefaultDocsConfig() DocsConfig {
+	return DocsConfig{
+		Renderer: DocsSwaggerUI,
+		Title:    "API References",
+		Path:     "/docs",
+		YAMLPath: "/openapi.yaml",
+		JSONPath: "/openapi.json",
+	}
+}
+
+// useDocs 注册文档页面、静态资源和规范的路由，release 模式下只在 cfg.Release 为 true 时注册。
+func (e *Engine) useDocs(cfg DocsConfig) {
+	if e.cfg.Mode == gin.ReleaseMode && !cfg.Release {
+		return
+	}
+
+	spec := e.cfg.OpenAPI.OpenAPI
+	r := e.Group("/", APIHidden)
+	r.Use(cfg.Middleware...)
+
+	var paths []string
+	if cfg.YAMLPath != "" {
+		r.GET(cfg.YAMLPath, serveSpec(spec, MIMEYAML))
+		paths = append(paths, cfg.YAMLPath)
+	}
+	if cfg.JSONPath != "" {
+		r.GET(cfg.JSONPath, serveSpec(spec, MIMEJSON))
+		paths = append(paths, cfg.JSONPath)
+	}
+
+	if cfg.Path != "" {
+		assets := cfg.Assets
+		if assets == nil {
+			assets, _ = fs.Sub(uiFS, path.Join("ui", string(cfg.Renderer)))
+		}
+
+		page, files, err := docsPage(&cfg, assets)
+		if err != nil {
+			panic(err)
+		}
+
+		r.GET(cfg.Path, He(func(c *Ctx) error {
+			return c.Content(MIMETextHTML).SendBytes(page)
+		}))
+
+		g := r.Group(path.Join(cfg.Path, "assets"))
+		g.Use(precompressed(http.FS(assets))) // go generate 会同时生成 .gz 文件
+		g.GET("/*filepath", He(func(c *Ctx) error {
+			name := strings.TrimPrefix(c.URI("filepath"), "/")
+			if _, ok := files[name]; !ok {
+				return ErrNotFound()
+			}
+			http.ServeFileFS(c.Writer, c.Request, assets, name)
+			return nil
+		}))
+
+		paths = append([]string{cfg.Path}, paths...)
+	}
+
+	if len(paths) > 0 {
+		debugInfo(fmt.Sprintf("OpenAPI 已开启，访问 %s 查看文档。", strings.Join(paths, "、")))
+	}
+}
+
+// docsPage 渲染文档页面，返回页面内容和渲染器的静态资源 (文件名 -> 下载地址)。
+// 页面引用 assets 中的文件，文件不存在时返回错误。
+func docsPage(cfg *DocsConfig, assets fs.FS) ([]byte, map[string]string, error) {
+	data, err := uiFS.ReadFile("ui/assets.json")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var manifest map[DocsRenderer]map[string]string
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, err
+	}
+
+	files, ok := manifest[cfg.Renderer]
+	if !ok {
+		return nil, nil, fmt.Errorf("sgin: unknown docs renderer %q", cfg.Renderer)
+	}
+
+	t, err := template.New("index.html").Funcs(template.FuncMap{
+		"asset": func(name string) (string, error) {
+			if _, err := fs.Stat(assets, name); err != nil {
+				return "", fmt.Errorf("sgin: docs renderer %q is missing asset %q, run go generate or set DocsConfig.Assets", cfg.Renderer, name)
+			}
+			return path.Join(cfg.Path, "assets", name), nil
+		},
+	}).ParseFS(uiFS, path.Join("ui", string(cfg.Renderer), "index.html"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	specURL := cfg.YAMLPath
+	if specURL == "" {
+		specURL = cfg.JSONPath
+	}
+
+	var buf bytes.Buffer
+	err = t.Execute(&buf, map[string]string{"Title": cfg.Title, "Spec": specURL})
+	return buf.Bytes(), files, err
+}
+
+// Spec 返回引擎的 OpenAPI 规范，未启用 OpenAPI 时返回 nil。
+// 路由注册后即可调用，无需启动服务器，可用于在构建时导出文档。
+func (e *Engine) Spec() *OpenAPI {
+	if e.cfg.OpenAPI == nil {
+		return nil
+	}
+	return e.cfg.OpenAPI.OpenAPI
+}
+
+// WriteSpec 将 OpenAPI 规范写入文件，扩展名为 .json 时使用 JSON 格式，否则使用 YAML 格式。
+func (e *Engine) WriteSpec(name string) error {
+	spec := e.Spec()
+	if spec == nil {
+		return errors.New("sgin: OpenAPI is not enabled")
+	}
+
+	var data []byte
+	var err error
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		data, err = spec.JSON()
+	} else {
+		data, err = spec.YAML()
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(name, data, 0o644)
+}
+
+// serveSpec 返回以 YAML 或 JSON 格式响应 OpenAPI 规范的处理器，
+// 响应携带 ETag，客户端可以通过 If-None-Match 重新验证缓存。
+func serveSpec(spec *OpenAPI, mime string) Handler {
+	return He(func(c *Ctx) error {
+		render := spec.YAML
+		if mime == MIMEJSON {
+			render = spec.JSON
+		}
+
+		data, err := render()
+		if err != nil {
+			return ErrInternalServerError().Wrap(err)
+		}
+
+		sum := sha256.Sum256(data)
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		c.Header(HeaderETag, etag)
+		c.Header(HeaderCacheControl, "no-cache") // 每次使用前重新验证，文档随路由变化。
+
+		if match := c.GetHeader(HeaderIfNoneMatch); match != "" && strings.Contains(match, etag) {
+			return c.Status(http.StatusNotModified).SendBytes(nil)
+		}
+		return c.Content(mime).SendBytes(data)
+	})
+}
@@ -0,0 +1,200 @@
+package sgin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestDocsDefaultIsSelfHosted(t *testing.T) {
+	e := testEngine(Config{OpenAPI: NewAPI()})
+
+	w := serve(e, http.MethodGet, "/docs", nil)
+	expectStatus(t, w, http.StatusOK)
+	body := w.Body.String()
+	if strings.Contains(body, "https://") {
+		t.Fatalf("default docs page references a CDN:\n%s", body)
+	}
+	for _, asset := range []string{"/docs/assets/swagger-ui-bundle.js", "/docs/assets/swagger-ui.css"} {
+		if !strings.Contains(body, asset) {
+			t.Fatalf("docs page does not reference %s", asset)
+		}
+		expectStatus(t, serve(e, http.MethodGet, asset, nil), http.StatusOK)
+	}
+
+	w = serve(e, http.MethodGet, "/docs/assets/swagger-ui-bundle.js", nil, HeaderAcceptEncoding, "gzip")
+	if w.Header().Get(HeaderContentEncoding) != "gzip" {
+		t.Fatal("embedded asset is not served precompressed")
+	}
+
+	expectStatus(t, serve(e, http.MethodGet, "/docs/assets/index.html", nil), http.StatusNotFound)
+}
+
+func TestDocsMissingAssets(t *testing.T) {
+	v := mustPanic(t, func() {
+		testEngine(Config{OpenAPI: NewAPI(), Docs: func(c *DocsConfig) {
+			c.Assets = fstest.MapFS{"swagger-ui.css": {Data: []byte("body{}")}}
+		}})
+	})
+	if !strings.Contains(fmt.Sprint(v), `missing asset "swagger-ui-bundle.js"`) {
+		t.Fatalf("unexpected panic %v", v)
+	}
+
+	v = mustPanic(t, func() {
+		testEngine(Config{OpenAPI: NewAPI(), Docs: func(c *DocsConfig) { c.Renderer = "elements" }})
+	})
+	if !strings.Contains(fmt.Sprint(v), "unknown docs renderer") {
+		t.Fatalf("unexpected panic %v", v)
+	}
+}
+
+func TestDocsCustomAssets(t *testing.T) {
+	e := testEngine(Config{OpenAPI: NewAPI(), Docs: func(c *DocsConfig) {
+		c.Assets = fstest.MapFS{
+			"swagger-ui-bundle.js": {Data: []byte("bundle()")},
+			"swagger-ui.css":       {Data: []byte("body{}")},
+		}
+	}})
+
+	w := serve(e, http.MethodGet, "/docs", nil)
+	if !strings.Contains(w.Body.String(), `src="/docs/assets/swagger-ui-bundle.js"`) {
+		t.Fatalf("custom asset not referenced:\n%s", w.Body.String())
+	}
+	if w = serve(e, http.MethodGet, "/docs/assets/swagger-ui-bundle.js", nil); w.Body.String() != "bundle()" {
+		t.Fatalf("asset body = %q", w.Body.String())
+	}
+}
+
+func TestDocsPathsAndRelease(t *testing.T) {
+	e := testEngine(Config{Mode: gin.ReleaseMode, OpenAPI: NewAPI()})
+	expectStatus(t, serve(e, http.MethodGet, "/docs", nil), http.StatusNotFound)
+	expectStatus(t, serve(e, http.MethodGet, "/openapi.yaml", nil), http.StatusNotFound)
+
+	auth := He(func(c *Ctx) error {
+		if c.GetHeader(HeaderAuthorization) != "secret" {
+			return ErrUnauthorized()
+		}
+		return c.Next()
+	})
+	e = testEngine(Config{Mode: gin.ReleaseMode, OpenAPI: NewAPI(), Docs: func(c *DocsConfig) {
+		c.Release = true
+		c.Middleware = []Handler{auth}
+		c.Path = "/api/docs"
+		c.YAMLPath = ""
+		c.JSONPath = "/api/spec.json"
+	}})
+	e.GET("/ping", He(func(c *Ctx) error { return c.Send("pong") }))
+
+	expectStatus(t, serve(e, http.MethodGet, "/api/docs", nil), http.StatusUnauthorized)
+	expectStatus(t, serve(e, http.MethodGet, "/api/spec.json", nil), http.StatusUnauthorized)
+	expectStatus(t, serve(e, http.MethodGet, "/api/docs/assets/swagger-ui.css", nil), http.StatusUnauthorized)
+	expectStatus(t, serve(e, http.MethodGet, "/openapi.yaml", nil, HeaderAuthorization, "secret"), http.StatusNotFound)
+
+	w := serve(e, http.MethodGet, "/api/docs", nil, HeaderAuthorization, "secret")
+	expectStatus(t, w, http.StatusOK)
+	if !strings.Contains(w.Body.String(), `url: "/api/spec.json"`) {
+		t.Fatalf("docs page does not point to the JSON spec:\n%s", w.Body.String())
+	}
+
+	w = serve(e, http.MethodGet, "/api/spec.json", nil, HeaderAuthorization, "secret")
+	expectStatus(t, w, http.StatusOK)
+	if !strings.Contains(w.Body.String(), `"/ping"`) || strings.Contains(w.Body.String(), "/api/docs") {
+		t.Fatalf("spec paths are wrong:\n%s", w.Body.String())
+	}
+}
+
+func TestDocsSpecCaching(t *testing.T) {
+	e := testEngine(Config{OpenAPI: NewAPI()})
+	e.GET("/ping", He(func(c *Ctx) error { return c.Send("pong") }))
+
+	w := serve(e, http.MethodGet, "/openapi.json", nil)
+	expectStatus(t, w, http.StatusOK)
+	var spec OpenAPI
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil || spec.Paths["/ping"] == nil || spec.Paths["/openapi.json"] != nil {
+		t.Fatalf("spec = %s, %v", w.Body.String(), err)
+	}
+	if ct := w.Header().Get(HeaderContentType); !strings.HasPrefix(ct, MIMEJSON) {
+		t.Fatalf("content type = %s", ct)
+	}
+	etag := w.Header().Get(HeaderETag)
+	if len(etag) != 34 || etag[0] != '"' || w.Header().Get(HeaderCacheControl) != "no-cache" {
+		t.Fatalf("ETag = %s, Cache-Control = %s", etag, w.Header().Get(HeaderCacheControl))
+	}
+
+	// 内容不变时 ETag 不变，If-None-Match 匹配时返回 304。
+	if again := serve(e, http.MethodGet, "/openapi.json", nil); again.Header().Get(HeaderETag) != etag {
+		t.Fatal("ETag is not stable")
+	}
+	for _, match := range []string{etag, `"other", ` + etag} {
+		w = serve(e, http.MethodGet, "/openapi.json", nil, HeaderIfNoneMatch, match)
+		expectStatus(t, w, http.StatusNotModified)
+		if w.Body.Len() != 0 || w.Header().Get(HeaderETag) != etag {
+			t.Fatalf("304 body = %q, ETag = %s", w.Body.String(), w.Header().Get(HeaderETag))
+		}
+	}
+	expectStatus(t, serve(e, http.MethodGet, "/openapi.json", nil, HeaderIfNoneMatch, `"other"`), http.StatusOK)
+
+	// YAML 使用各自的 ETag
+	w = serve(e, http.MethodGet, "/openapi.yaml", nil)
+	expectStatus(t, w, http.StatusOK)
+	if !strings.HasPrefix(w.Header().Get(HeaderContentType), MIMEYAML) || !strings.Contains(w.Body.String(), "/ping:") {
+		t.Fatalf("yaml = %s", w.Body.String())
+	}
+	if w.Header().Get(HeaderETag) == etag {
+		t.Fatal("JSON and YAML share an ETag")
+	}
+
+	// 注册新路由后 ETag 随之变化，旧的缓存不再有效。
+	e.GET("/pong", He(func(c *Ctx) error { return c.Send("ping") }))
+	w = serve(e, http.MethodGet, "/openapi.json", nil, HeaderIfNoneMatch, etag)
+	expectStatus(t, w, http.StatusOK)
+	if w.Header().Get(HeaderETag) == etag || !strings.Contains(w.Body.String(), `"/pong"`) {
+		t.Fatalf("ETag = %s after adding a route", w.Header().Get(HeaderETag))
+	}
+}
+
+func TestWriteSpec(t *testing.T) {
+	e := testEngine(Config{OpenAPI: NewAPI()})
+	e.GET("/ping", He(func(c *Ctx) error { return c.Send("pong") }))
+
+	dir := t.TempDir()
+	for _, name := range []string{"openapi.json", "OPENAPI.JSON", "openapi.yaml", "openapi"} {
+		file := filepath.Join(dir, name)
+		if err := e.WriteSpec(file); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want, _ := e.Spec().YAML()
+		if strings.EqualFold(filepath.Ext(name), ".json") {
+			want, _ = e.Spec().JSON()
+			if !json.Valid(data) {
+				t.Fatalf("%s is not JSON:\n%s", name, data)
+			}
+		}
+		if !bytes.Equal(data, want) {
+			t.Fatalf("%s =\n%s\nwant\n%s", name, data, want)
+		}
+	}
+
+	if err := e.WriteSpec(filepath.Join(dir, "missing", "openapi.json")); err == nil {
+		t.Fatal("writing to a missing directory succeeded")
+	}
+	if err := testEngine().WriteSpec(filepath.Join(dir, "off.json")); err == nil {
+		t.Fatal("WriteSpec succeeded without OpenAPI")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "off.json")); !os.IsNotExist(err) {
+		t.Fatal("WriteSpec created a file without OpenAPI")
+	}
+}
@@ -1,9 +1,8 @@
 package sgin
 
 import (
-	"errors"
 	"net"
-	"net/http"
+	"reflect"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -20,6 +19,13 @@ type Engine struct {
 	languageMatcher language.Matcher
 	translator      *ut.UniversalTranslator
 	defaultLang     language.Tag
+	codecs          *codecs
+	json            *jsonCodec
+	cookies         *secureCookies
+	errorRules      []errorRule
+	reporter        *reporter
+	envelope        *EnvelopeConfig
+	page            PageConfig
 }
 
 type Config struct {
@@ -27,24 +33,27 @@ type Config struct {
 	TrustedProxies []string                    // gin.SetTrustedProxies
 	Recovery       func(c *Ctx, out, s string) // 回调 [带颜色的控制台输出] 和 [结构化 JSON 日志]
 	ErrorHandler   func(c *Ctx, err error) error
+	Problem        bool                               // 以问题详情 (RFC 9457) 响应错误：ErrorHandler 为空时使用 ProblemErrorHandler，并在文档中为每个操作记录。
 	Logger         func(c *Ctx, out string, s string) // 回调 [带颜色的控制台输出] 和 [结构化 JSON 日志]
 	Cors           func(*cors.Config)                 // 默认配置 cors.DefaultConfig()
+	Compress       func(*CompressConfig)              // 开启响应压缩，默认配置 DefaultCompressConfig()。
+	JSON           func(*JSONConfig)                  // JSON 编解码配置，默认配置 DefaultJSONConfig()。
+	Cookie         func(*CookieConfig)                // 签名/加密 Cookie 的密钥和属性，默认配置 DefaultCookieConfig()。
+	Session        func(*SessionConfig)               // 开启会话，默认配置 DefaultSessionConfig()。
+	Report         func(*ReportConfig)                // 上报 panic 和 5xx 错误，默认配置 DefaultReportConfig()。
+	Envelope       func(*EnvelopeConfig)              // 将处理器的输出和错误包装为统一结构，默认配置 DefaultEnvelopeConfig()。
+	Page           func(*PageConfig)                  // 分页查询的默认和最大数量，默认配置 DefaultPageConfig()。
+	Docs           func(*DocsConfig)                  // 文档页面的渲染器和路由，默认配置 DefaultDocsConfig()。
 	OpenAPI        *API
-	Locales        []language.Tag // 绑定验证错误所使用的多语言支持
+	Schemas        map[reflect.Type]*Schema // 覆盖类型在文档中的 Schema，如 reflect.TypeFor[decimal.Decimal]()。
+	Locales        []language.Tag           // 绑定验证错误所使用的多语言支持
+	Catalog        *Catalog                 // 错误消息目录，ErrorHandler 按请求语言渲染 *Error 的消息。
 }
 
 // DefaultErrorHandler 默认的错误处理器
 func DefaultErrorHandler(c *Ctx, err error) error {
-	code := http.StatusInternalServerError
-
-	var e *Error
-	if errors.As(err, &e) && e.Code > 0 { // 如果是 *Error 错误
-		code = e.Code
-	} else if stc := c.StatusCode(); stc != 200 && stc != 0 {
-		code = stc
-	}
-
-	return c.Content(MIMETextPlain).Status(code).Send(err.Error())
+	writeErrorHeaders(c, err)
+	return c.Content(MIMETextPlain).Status(errorStatus(c, err)).Send(c.errorMessage(err))
 }
 
 // DefaultConfig 默认配置
@@ -54,7 +63,11 @@ func DefaultConfig(config ...Config) (cfg Config) {
 	}
 
 	if cfg.ErrorHandler == nil {
-		cfg.ErrorHandler = DefaultErrorHandler
+		if cfg.Problem {
+			cfg.ErrorHandler = ProblemErrorHandler
+		} else {
+			cfg.ErrorHandler = DefaultErrorHandler
+		}
 	}
 
 	return cfg
@@ -64,7 +77,62 @@ func New(config ...Config) *Engine {
 	cfg := DefaultConfig(config...)
 	gin.SetMode(cfg.Mode)
 
-	e := &Engine{engine: gin.New(), cfg: cfg}
+	jsonCfg := DefaultJSONConfig()
+	if cfg.JSON != nil {
+		cfg.JSON(&jsonCfg)
+	}
+
+	e := &Engine{engine: gin.New(), cfg: cfg, json: newJSONCodec(jsonCfg)}
+	e.cfg.ErrorHandler = func(c *Ctx, err error) error {
+		err = e.mapError(err) // 先按 MapError 注册的规则转换错误
+		e.reportError(c, err)
+		if c.envelopeError(err) {
+			return nil
+		}
+		return cfg.ErrorHandler(c, err)
+	}
+	e.codecs = newCodecs(e.json)
+
+	e.page = DefaultPageConfig()
+	if cfg.Page != nil {
+		cfg.Page(&e.page)
+	}
+
+	if cfg.Cookie != nil {
+		cookieCfg := DefaultCookieConfig()
+		cfg.Cookie(&cookieCfg)
+
+		var err error
+		if e.cookies, err = newSecureCookies(cookieCfg); err != nil {
+			panic(err)
+		}
+	}
+	if cfg.Report != nil {
+		reportCfg := DefaultReportConfig()
+		cfg.Report(&reportCfg)
+		if reportCfg.Reporter == nil {
+			panic("sgin: ReportConfig.Reporter is required")
+		}
+		e.reporter = newReporter(reportCfg)
+	}
+	if cfg.Envelope != nil {
+		envelopeCfg := DefaultEnvelopeConfig()
+		cfg.Envelope(&envelopeCfg)
+		e.envelope = &envelopeCfg
+	}
+	if cfg.OpenAPI != nil {
+		cfg.OpenAPI.envelope = e.envelope
+		cfg.OpenAPI.page = &e.page
+		if cfg.OpenAPI.ProblemResponses == nil { // 未显式设置时跟随 Config.Problem
+			cfg.OpenAPI.ProblemResponses = &cfg.Problem
+		}
+		cfg.OpenAPI.codecs = e.codecs // 文档中的媒体类型与已注册的编解码器保持一致
+		cfg.OpenAPI.Components.Schemas.json = e.json
+		for t, s := range cfg.Schemas {
+			cfg.OpenAPI.Components.Schemas.Override(t, s)
+		}
+	}
+
 	e.Router = Router{
 		i:    e.engine,
 		e:    e,
@@ -80,20 +148,13 @@ func New(config ...Config) *Engine {
 		debugWarning(err.Error())
 	}
 
-	// OpenAPI 文档中间件
-	if cfg.OpenAPI != nil && cfg.Mode != gin.ReleaseMode {
-		e.GET("/openapi.yaml", He(func(c *Ctx) error {
-			if specYAML, err := cfg.OpenAPI.YAML(); err == nil {
-				return c.Content(MIMEYAML).Send(string(specYAML))
-			}
-			return c.Send(ErrInternalServerError())
-		}), APIHidden)
-
-		e.GET("/docs", He(func(c *Ctx) error {
-			return c.Content(MIMETextHTML).Send(DocsHTML)
-		}), APIHidden)
-
-		debugInfo("OpenAPI 已开启，访问 /docs 或 /openapi.yaml 查看文档。")
+	// OpenAPI 文档路由
+	if cfg.OpenAPI != nil {
+		docsCfg := DefaultDocsConfig()
+		if cfg.Docs != nil {
+			cfg.Docs(&docsCfg)
+		}
+		e.useDocs(docsCfg)
 	}
 
 	return e
@@ -141,4 +202,16 @@ func (e *Engine) useMiddleware() {
 		cfg.Cors(&corsCfg)
 		e.Use(cors.New(corsCfg))
 	}
+
+	if cfg.Compress != nil {
+		compressCfg := DefaultCompressConfig()
+		cfg.Compress(&compressCfg)
+		e.Use(Compress(compressCfg))
+	}
+
+	if cfg.Session != nil {
+		sessionCfg := DefaultSessionConfig()
+		cfg.Session(&sessionCfg)
+		e.Use(Sessions(sessionCfg))
+	}
 }
@@ -0,0 +1,160 @@
+package sgin
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+
+	"github.com/baagod/sgin/v2/helper"
+	"github.com/gin-gonic/gin"
+)
+
+const rawResponseKey = "_baa/sgin/raw"
+
+// EnvelopeConfig 定义统一响应包装的配置
+type EnvelopeConfig struct {
+	Type  reflect.Type                // 包装结构的类型，默认 Result。处理器已返回该类型 (或同一泛型的其他实例) 时不再包装。
+	Field string                      // 包装结构中保存处理器输出的 JSON 字段，用于生成文档，默认 "data"。
+	Data  func(c *Ctx, data any) any  // 包装处理器的输出
+	Error func(c *Ctx, err error) any // 包装错误，响应的 HTTP 状态码仍由错误决定。
+}
+
+// DefaultEnvelopeConfig 返回默认的响应包装配置：
+// 输出包装为 Status=1 的 Result，错误包装为 Status=0、Code 为错误状态码的 Result。
+func DefaultEnvelopeConfig() EnvelopeConfig {
+	return EnvelopeConfig{
+		Type:  reflect.TypeFor[Result](),
+		Field: "data",
+		Data: func(_ *Ctx, data any) any {
+			r := &Result{Status: 1, Data: data}
+			if p, ok := asPager(data); ok {
+				r.Count = p.pageTotal() // 分页结果的总数
+			}
+			return r
+		},
+		Error: func(c *Ctx, err error) any {
+			status := errorStatus(c, err)
+			msg := c.statusTitle(status) // 非 *Error 的 5xx 错误不暴露内部错误信息
+			if e := (*Error)(nil); errors.As(err, &e) || status < 500 {
+				msg = c.errorMessage(err)
+			}
+			return &Result{Code: status, Msg: msg}
+		},
+	}
+}
+
+// RawResponse 使路由的输出和错误不经过统一响应包装
+func RawResponse(op *Operation) {
+	op.RawResponse = true
+}
+
+// wraps 报告类型 t 是否需要包装，已是包装类型 (或同一泛型的其他实例) 时不再包装。
+func (cfg *EnvelopeConfig) wraps(t reflect.Type) bool {
+	if t = helper.Deref(t); t == cfg.Type {
+		return false
+	}
+
+	name, _, _ := strings.Cut(t.Name(), "[")
+	base, _, _ := strings.Cut(cfg.Type.Name(), "[")
+	return t.PkgPath() != cfg.Type.PkgPath() || name != base
+}
+
+// envelope 包装处理器的输出，流式响应和 CSV 导出不包装。
+func (c *Ctx) envelope(data any) any {
+	cfg := c.engine.envelope
+	if cfg == nil || c.Get(rawResponseKey) != nil {
+		return data
+	}
+
+	t := reflect.TypeOf(data)
+	if _, ok := streamElem(t); ok || c.wantCSV(data) || !cfg.wraps(t) {
+		return data
+	}
+
+	return cfg.Data(c, data)
+}
+
+// envelopeError 以包装结构响应错误，返回 false 表示路由未启用包装。
+func (c *Ctx) envelopeError(err error) bool {
+	cfg := c.engine.envelope
+	if cfg == nil || c.Get(rawResponseKey) != nil {
+		return false
+	}
+
+	status := errorStatus(c, err)
+	if status >= 500 {
+		_ = c.ctx.Error(err) // 由 Logger 记录原始错误
+	}
+
+	writeErrorHeaders(c, err)
+	_ = c.Status(status).Send(cfg.Error(c, err))
+	return true
+}
+
+// rawResponse 是标记路由不经过统一响应包装的中间件
+func rawResponse(gc *gin.Context) {
+	gc.Set(rawResponseKey, true)
+}
+
+// routeHandlers 返回注册到 gin 的处理器链
+func (r *Router) routeHandlers(op *Operation, h Handler) []Handler {
+	if r.e.envelope != nil && op.RawResponse {
+		return []Handler{rawResponse, h}
+	}
+	return []Handler{h}
+}
+
+// envelopeKey 标识包装类型 Envelope 与数据类型 Data 生成的派生组件
+type envelopeKey struct {
+	Envelope, Data reflect.Type
+}
+
+func (k envelopeKey) String() string {
+	return fmt.Sprintf("%s wrapping %s", k.Envelope, k.Data)
+}
+
+// envelopeSchema 返回类型 t 包装后的 Schema，组件以包装类型和 t 的组件名称拼接命名 (如 ResultUser)。
+func (a *API) envelopeSchema(t reflect.Type) *Schema {
+	cfg := a.envelope
+	if !cfg.wraps(t) {
+		return a.Schema(t)
+	}
+
+	t = helper.Deref(t) // *User 与 User 包装后的结构相同
+	r := a.Components.Schemas
+	base := strings.TrimPrefix(a.Schema(cfg.Type).Ref, r.Prefix)
+	src := r.schemas[base]
+	if src == nil {
+		panic(fmt.Errorf("sgin: envelope type %s must be a struct", cfg.Type))
+	}
+
+	data := a.Schema(t)
+	name := strings.TrimPrefix(data.Ref, r.Prefix) // 结构体使用组件名称，其他类型 (如 []User) 使用可读的类型名称。
+	if data.Ref == "" {
+		name = typeExprName(t.String())
+	}
+
+	return r.derive(base+name, envelopeKey{cfg.Type, t}, func() *Schema {
+		s := *src
+		s.Properties = maps.Clone(src.Properties)
+		s.Properties[cfg.Field] = data
+		return &s
+	})
+}
+
+// parseEnvelopeError 为 Operation 注入包装结构的 default 错误响应
+func (a *API) parseEnvelopeError(op *Operation) {
+	if _, ok := op.Responses["default"]; ok {
+		return
+	}
+
+	schema := a.Schema(a.envelope.Type)
+	content := map[string]*MediaType{}
+	for _, m := range a.mediaTypes(a.envelope.Type) {
+		content[m] = &MediaType{Schema: schema}
+	}
+
+	op.Responses["default"] = &ResponseBody{Description: "Error", Content: content}
+}
@@ -0,0 +1,83 @@
+package sgin
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type EnvUser struct {
+	ID int `json:"id"`
+}
+
+// ResultEnvUser 与包装 EnvUser 生成的组件同名
+type ResultEnvUser struct {
+	Name string `json:"name"`
+}
+
+func envelopeEngine() *Engine {
+	return testEngine(Config{OpenAPI: NewAPI(), Envelope: func(*EnvelopeConfig) {}})
+}
+
+func TestEnvelopeResponse(t *testing.T) {
+	e := envelopeEngine()
+	e.GET("/user", H(func(c *Ctx, _ struct{}) (*EnvUser, error) {
+		return &EnvUser{ID: 1}, nil
+	}))
+	e.GET("/fail", He(func(c *Ctx) error {
+		return ErrNotFound()
+	}))
+
+	w := serve(e, http.MethodGet, "/user", nil)
+	expectStatus(t, w, http.StatusOK)
+	if body := w.Body.String(); !strings.Contains(body, `"status":1`) || !strings.Contains(body, `"data":{"id":1}`) {
+		t.Fatalf("body = %s", body)
+	}
+
+	w = serve(e, http.MethodGet, "/fail", nil)
+	expectStatus(t, w, http.StatusNotFound)
+	if body := w.Body.String(); !strings.Contains(body, `"code":404`) || !strings.Contains(body, `"status":0`) {
+		t.Fatalf("body = %s", body)
+	}
+}
+
+func TestEnvelopeSchemaNames(t *testing.T) {
+	e := envelopeEngine()
+	e.GET("/a", H(func(c *Ctx, _ struct{}) (*EnvUser, error) { return nil, nil }))
+	e.GET("/b", H(func(c *Ctx, _ struct{}) (EnvUser, error) { return EnvUser{}, nil }))
+	e.GET("/c", H(func(c *Ctx, _ struct{}) ([]EnvUser, error) { return nil, nil }))
+	e.GET("/d", H(func(c *Ctx, _ struct{}) (*ResultOf[EnvUser], error) { return nil, nil }))
+
+	schemas := e.Spec().Components.Schemas.schemas
+	for _, name := range []string{"Result", "EnvUser", "ResultEnvUser", "ResultEnvUserList", "ResultOfEnvUser"} {
+		if schemas[name] == nil {
+			t.Fatalf("missing component %s", name)
+		}
+	}
+	if ref := schemas["ResultEnvUser"].Properties["data"].Ref; !strings.HasSuffix(ref, "/EnvUser") {
+		t.Fatalf("ResultEnvUser.data = %q", ref)
+	}
+	if schemas["ResultOfEnvUserEnvUser"] != nil {
+		t.Fatal("ResultOf[EnvUser] should not be wrapped")
+	}
+}
+
+func TestEnvelopeNameCollision(t *testing.T) {
+	e := envelopeEngine()
+	e.GET("/a", H(func(c *Ctx, _ struct{}) (*EnvUser, error) { return nil, nil }))
+	v := mustPanic(t, func() { e.Spec().Components.Schemas.Schema(reflect.TypeFor[ResultEnvUser]()) })
+	if !strings.Contains(fmt.Sprint(v), "duplicate name: ResultEnvUser") {
+		t.Fatalf("unexpected panic %v", v)
+	}
+
+	e = envelopeEngine()
+	e.Spec().Components.Schemas.Schema(reflect.TypeFor[ResultEnvUser]())
+	v = mustPanic(t, func() {
+		e.GET("/a", H(func(c *Ctx, _ struct{}) (*EnvUser, error) { return nil, nil }))
+	})
+	if !strings.Contains(fmt.Sprint(v), "duplicate name: ResultEnvUser") {
+		t.Fatalf("unexpected panic %v", v)
+	}
+}
@@ -1,19 +1,174 @@
 package sgin
 
 import (
+    "errors"
+    "maps"
     "net/http"
+    "strconv"
+    "time"
 )
 
 // Error 是 APIError 的默认实现
 type Error struct {
-    Code    int
-    Message string
+    Code    int            // HTTP 状态码
+    Message string         // 返回给客户端的错误消息
+    Reason  string         // 业务错误码，如 "USER_NOT_FOUND"。
+    Details map[string]any // 机器可读的错误详情
+    Headers http.Header    // 由 ErrorHandler 写入响应头，如 Retry-After、WWW-Authenticate。
+    Err     error          // 原始错误，可通过 errors.Is/As 访问。
+    Key     string         // 消息目录 (Config.Catalog) 中的消息键，由 ErrorHandler 按请求语言渲染。
+    Args    map[string]any // 消息模板的参数
 }
 
 func (e *Error) Error() string {
     return e.Message
 }
 
+// Unwrap 返回原始错误
+func (e *Error) Unwrap() error {
+    return e.Err
+}
+
+// Wrap 设置原始错误
+func (e *Error) Wrap(err error) *Error {
+    e.Err = err
+    return e
+}
+
+// WithReason 设置业务错误码
+func (e *Error) WithReason(reason string) *Error {
+    e.Reason = reason
+    return e
+}
+
+// WithKey 设置消息目录中的消息键和模板参数
+func (e *Error) WithKey(key string, args ...map[string]any) *Error {
+    e.Key = key
+    if len(args) > 0 {
+        e.Args = args[0]
+    }
+    return e
+}
+
+// WithDetail 添加一项错误详情
+func (e *Error) WithDetail(key string, value any) *Error {
+    if e.Details == nil {
+        e.Details = map[string]any{}
+    }
+    e.Details[key] = value
+    return e
+}
+
+// WithDetails 合并多项错误详情
+func (e *Error) WithDetails(details map[string]any) *Error {
+    if e.Details == nil {
+        e.Details = map[string]any{}
+    }
+    maps.Copy(e.Details, details)
+    return e
+}
+
+// WithHeader 添加一个响应头
+func (e *Error) WithHeader(key, value string) *Error {
+    if e.Headers == nil {
+        e.Headers = http.Header{}
+    }
+    e.Headers.Add(key, value)
+    return e
+}
+
+// RetryAfter 设置 Retry-After 响应头 (秒)，常用于 429 和 503。
+func (e *Error) RetryAfter(d time.Duration) *Error {
+    if e.Headers == nil {
+        e.Headers = http.Header{}
+    }
+    e.Headers.Set(HeaderRetryAfter, strconv.Itoa(int((d+time.Second-1)/time.Second)))
+    return e
+}
+
+// errorStatus 返回错误对应的响应状态码：*Error 和 *Problem 使用自身的状态码，
+// 否则使用已设置的非 200 状态码，默认为 500。
+func errorStatus(c *Ctx, err error) int {
+    var e *Error
+    if errors.As(err, &e) && e.Code > 0 {
+        return e.Code
+    }
+
+    var p *Problem
+    if errors.As(err, &p) && p.Status > 0 {
+        return p.Status
+    }
+
+    if stc := c.StatusCode(); stc != 200 && stc != 0 {
+        return stc
+    }
+
+    return http.StatusInternalServerError
+}
+
+// errorRule 是一条错误映射规则，返回 nil 表示不匹配。
+type errorRule func(err error) *Error
+
+// MapError 将与 target 匹配 (errors.Is) 的错误映射为 code 状态码，msg 默认为 http.StatusText(code)。
+// 规则按注册顺序在交给 ErrorHandler 之前求值，已经是 *Error 或 *Problem 的错误不会被映射。
+//
+//	r.MapError(sql.ErrNoRows, http.StatusNotFound)
+//	r.MapError(context.DeadlineExceeded, http.StatusGatewayTimeout, "upstream timeout")
+func (e *Engine) MapError(target error, code int, msg ...string) *Engine {
+    return e.MapErrorFunc(func(err error) *Error {
+        if errors.Is(err, target) {
+            return NewError(code, msg...)
+        }
+        return nil
+    })
+}
+
+// MapErrorFunc 注册一个映射函数，f 返回非 nil 的 *Error 时使用它 (原始错误会被自动包装)。
+func (e *Engine) MapErrorFunc(f func(err error) *Error) *Engine {
+    e.errorRules = append(e.errorRules, f)
+    return e
+}
+
+// mapError 按注册的规则将任意错误转换为 *Error
+func (e *Engine) mapError(err error) error {
+    if len(e.errorRules) == 0 || err == nil {
+        return err
+    }
+
+    var he *Error
+    var p *Problem
+    if errors.As(err, &he) || errors.As(err, &p) {
+        return err
+    }
+
+    for _, rule := range e.errorRules {
+        if mapped := rule(err); mapped != nil {
+            e := *mapped // 规则可能返回共享的 *Error，复制后再包装原始错误。
+            if e.Err == nil {
+                e.Err = err
+            }
+            return &e
+        }
+    }
+
+    return err
+}
+
+// writeErrorHeaders 将错误链中 *Error 的 Headers 写入响应
+func writeErrorHeaders(c *Ctx, err error) {
+    var e *Error
+    if !errors.As(err, &e) {
+        return
+    }
+
+    h := c.Writer.Header()
+    for key, values := range e.Headers {
+        for _, v := range values {
+            h.Add(key, v)
+        }
+    }
+}
+
 // NewError 创建一个新的 Error
 // 如果没有提供消息，将使用 http.StatusText(code) 作为默认消息
 func NewError(code int, msg ...string) *Error {
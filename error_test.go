@@ -0,0 +1,155 @@
+package sgin
+
+import (
+    "database/sql"
+    "errors"
+    "fmt"
+    "maps"
+    "net/http"
+    "slices"
+    "strings"
+    "sync"
+    "testing"
+    "time"
+)
+
+func TestMapError(t *testing.T) {
+    e := testEngine()
+    e.MapError(sql.ErrNoRows, http.StatusNotFound)
+    e.GET("/", He(func(c *Ctx) error {
+        return sql.ErrNoRows
+    }))
+
+    expectStatus(t, serve(e, http.MethodGet, "/", nil), http.StatusNotFound)
+}
+
+func TestMapErrorSharedSentinel(t *testing.T) {
+    errGone := NewError(http.StatusGone, "gone")
+    errA, errB := errors.New("a"), errors.New("b")
+
+    e := testEngine()
+    e.MapErrorFunc(func(err error) *Error {
+        if errors.Is(err, errA) || errors.Is(err, errB) {
+            return errGone
+        }
+        return nil
+    })
+
+    var wg sync.WaitGroup
+    for _, src := range []error{errA, errB, errA, errB} {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            mapped := e.mapError(src)
+            if !errors.Is(mapped, src) {
+                t.Errorf("mapped error %v does not wrap %v", mapped, src)
+            }
+        }()
+    }
+    wg.Wait()
+
+    if errGone.Err != nil {
+        t.Fatalf("shared error was mutated: %v", errGone.Err)
+    }
+}
+
+func TestErrorBuilders(t *testing.T) {
+    cause := errors.New("db down")
+    e := ErrServiceUnavailable().
+        Wrap(cause).
+        WithReason("DB_DOWN").
+        WithKey("db.down", map[string]any{"db": "users"}).
+        WithDetail("db", "users").
+        WithDetail("db", "orders").
+        WithDetails(map[string]any{"retry": true}).
+        WithHeader("X-Region", "a").
+        WithHeader("X-Region", "b").
+        RetryAfter(1500 * time.Millisecond)
+
+    if e.Code != http.StatusServiceUnavailable || e.Error() != http.StatusText(http.StatusServiceUnavailable) {
+        t.Fatalf("code = %d, message = %q", e.Code, e.Error())
+    }
+    if !errors.Is(e, cause) || e.Reason != "DB_DOWN" || e.Key != "db.down" || e.Args["db"] != "users" {
+        t.Fatalf("error = %+v", e)
+    }
+    if !maps.Equal(e.Details, map[string]any{"db": "orders", "retry": true}) {
+        t.Fatalf("details = %v", e.Details)
+    }
+    if got := e.Headers.Values("X-Region"); !slices.Equal(got, []string{"a", "b"}) {
+        t.Fatalf("X-Region = %v", got)
+    }
+    if got := e.Headers.Get(HeaderRetryAfter); got != "2" { // 向上取整为秒
+        t.Fatalf("Retry-After = %s", got)
+    }
+    if e.RetryAfter(time.Minute); len(e.Headers.Values(HeaderRetryAfter)) != 1 || e.Headers.Get(HeaderRetryAfter) != "60" {
+        t.Fatalf("Retry-After = %v", e.Headers.Values(HeaderRetryAfter))
+    }
+
+    // WithKey 不传参数时保留已有参数
+    if e.WithKey("db.other"); e.Key != "db.other" || e.Args["db"] != "users" {
+        t.Fatalf("key = %s, args = %v", e.Key, e.Args)
+    }
+
+    for _, tt := range []struct {
+        err  *Error
+        code int
+        msg  string
+    }{
+        {NewError(418), http.StatusTeapot, http.StatusText(http.StatusTeapot)},
+        {NewError(http.StatusConflict, "taken"), http.StatusConflict, "taken"},
+        {NotModified(), http.StatusNotModified, http.StatusText(http.StatusNotModified)},
+        {ErrBadRequest("bad"), http.StatusBadRequest, "bad"},
+        {ErrTooManyRequests(), http.StatusTooManyRequests, http.StatusText(http.StatusTooManyRequests)},
+        {ErrNotExtended(), http.StatusNotExtended, http.StatusText(http.StatusNotExtended)},
+    } {
+        if tt.err.Code != tt.code || tt.err.Message != tt.msg {
+            t.Errorf("%d: got %d %q", tt.code, tt.err.Code, tt.err.Message)
+        }
+    }
+}
+
+func TestErrorResponse(t *testing.T) {
+    handler := He(func(c *Ctx) error {
+        err := ErrTooManyRequests("slow down").
+            WithReason("RATE_LIMITED").
+            WithDetail("limit", 10).
+            WithHeader(HeaderWWWAuthenticate, `Bearer realm="api"`).
+            RetryAfter(30 * time.Second)
+        return fmt.Errorf("quota: %w", err) // 包装后仍按 *Error 处理
+    })
+
+    tests := []struct {
+        name string
+        cfg  Config
+        want []string
+    }{
+        {"default", Config{}, []string{"quota: slow down"}},
+        {"problem", Config{Problem: true}, []string{`"status":429`, `"detail":"quota: slow down"`, `"reason":"RATE_LIMITED"`, `"details":{"limit":10}`}},
+        {"envelope", Config{Envelope: func(*EnvelopeConfig) {}}, []string{`"code":429`, `"msg":"quota: slow down"`}},
+    }
+
+    for _, tt := range tests {
+        e := testEngine(tt.cfg)
+        e.GET("/", handler)
+        w := serve(e, http.MethodGet, "/", nil)
+        expectStatus(t, w, http.StatusTooManyRequests)
+
+        if w.Header().Get(HeaderRetryAfter) != "30" || w.Header().Get(HeaderWWWAuthenticate) != `Bearer realm="api"` {
+            t.Errorf("%s: headers = %v", tt.name, w.Header())
+        }
+        for _, want := range tt.want {
+            if !strings.Contains(w.Body.String(), want) {
+                t.Errorf("%s: body = %s, want %s", tt.name, w.Body.String(), want)
+            }
+        }
+    }
+
+    // 非 *Error 的 5xx 错误不暴露内部消息
+    e := testEngine(Config{Envelope: func(*EnvelopeConfig) {}})
+    e.GET("/", He(func(c *Ctx) error { return errors.New("dsn=secret") }))
+    w := serve(e, http.MethodGet, "/", nil)
+    expectStatus(t, w, http.StatusInternalServerError)
+    if strings.Contains(w.Body.String(), "secret") {
+        t.Fatalf("body = %s", w.Body.String())
+    }
+}
@@ -2,13 +2,17 @@ package sgin
 
 import (
     "errors"
+    "fmt"
+    "net/http"
     "reflect"
+    "runtime"
     "sync"
     "unsafe"
 
     "github.com/baagod/sgin/v2/helper"
     "github.com/gin-gonic/gin"
     "github.com/gin-gonic/gin/binding"
+    ut "github.com/go-playground/universal-translator"
     "github.com/go-playground/validator/v10"
 )
 
@@ -19,6 +23,10 @@ var (
 
 type HandleArg struct {
     In, Out reflect.Type
+    Media    []string       // 响应的媒体类型，为空时使用已注册编解码器的媒体类型。
+    Stream   bool           // 是否为流式响应，此时 Out 为元素类型。
+    Messages []reflect.Type // WebSocket 的 [接收, 发送] 消息类型
+    Func     string         // 处理器函数的完整名称 (如 main.GetUser)，用于生成 operationId。
 }
 
 type HandleMeta struct {
@@ -81,7 +89,11 @@ func H[I any, R any](f func(*Ctx, I) (R, error)) Handler {
             result, err := bindV3(c, tIn, ptrIn)
             if err != nil {
                 gc.Abort()
-                _ = e.cfg.ErrorHandler(c, ErrBadRequest(err.Error()))
+                verr, he := (*ValidationError)(nil), (*Error)(nil)
+                if !errors.As(err, &verr) && !errors.As(err, &he) { // 保留 415 等已确定状态码的错误
+                    err = ErrBadRequest(err.Error())
+                }
+                _ = e.cfg.ErrorHandler(c, err)
                 return
             }
             in = result.(I)
@@ -90,30 +102,55 @@ func H[I any, R any](f func(*Ctx, I) (R, error)) Handler {
         c.send(f(c, in))
     }
 
-    hMeta.Set(h, &HandleArg{In: tIn, Out: tOut}) // 注册元数据
+    arg := &HandleArg{In: tIn, Out: tOut, Func: funcName(f)}
+    if elem, ok := streamElem(tOut); ok { // 迭代器或通道以流的形式响应
+        arg.Out, arg.Stream = elem, true
+        arg.Media = []string{MIMEJSON, MIMENDJSON, MIMETextEventStream}
+        if isCSVRow(elem) {
+            arg.Media = append(arg.Media, MIMECSV)
+        }
+    }
+
+    hMeta.Set(h, arg) // 注册元数据
     return h
 }
 
 // Ho 创建一个仅有 [输出] 的强类型处理器 (支持 OpenAPI)
 func Ho[I any, R any](f func(*Ctx, I) R) Handler {
-    return H(func(c *Ctx, in I) (R, error) {
+    return named(H(func(c *Ctx, in I) (R, error) {
         return f(c, in), nil
-    })
+    }), f)
 }
 
 // He 创建一个无输入且仅返回 error 的处理器方法
 func He(f func(*Ctx) error) Handler {
-    return H(func(c *Ctx, _ struct{}) (any, error) {
+    return named(H(func(c *Ctx, _ struct{}) (any, error) {
         return nil, f(c)
-    })
+    }), f)
 }
 
 // Hn 创建一个无输入输出的处理器方法
 func Hn(f func(*Ctx)) Handler {
-    return H(func(c *Ctx, _ struct{}) (any, error) {
+    return named(H(func(c *Ctx, _ struct{}) (any, error) {
         f(c)
         return nil, nil
-    })
+    }), f)
+}
+
+// named 将处理器元数据中的函数名称替换为 f 的名称 (包装后的闭包名称没有意义)
+func named(h Handler, f any) Handler {
+    if a, ok := hMeta.Get(h); ok {
+        a.Func = funcName(f)
+    }
+    return h
+}
+
+// funcName 返回函数的完整名称
+func funcName(f any) string {
+    if fn := runtime.FuncForPC(reflect.ValueOf(f).Pointer()); fn != nil {
+        return fn.Name()
+    }
+    return ""
 }
 
 func bindV3(c *Ctx, t reflect.Type, ptr bool) (_ any, err error) {
@@ -127,17 +164,21 @@ func bindV3(c *Ctx, t reflect.Type, ptr bool) (_ any, err error) {
         gc.ShouldBindHeader,
         gc.ShouldBindQuery,
         func(o any) error {
-            b := binding.Default(gc.Request.Method, gc.ContentType())
-
-            // 针对会消耗 Body 的格式使用 BindingBody 进行缓存式绑定
-            if b == binding.JSON || b == binding.XML || b == binding.YAML {
-                if bb, ok := b.(binding.BindingBody); ok {
-                    return gc.ShouldBindBodyWith(o, bb)
+            // 已注册编解码器的格式从缓存的 Body 中解码，允许重复读取。
+            if gc.Request.Method != http.MethodGet {
+                if codec := c.engine.codecs.get(gc.ContentType()); codec != nil {
+                    if !supports(codec, t) {
+                        return ErrUnsupportedMediaType(fmt.Sprintf("unsupported content type %q", gc.ContentType()))
+                    }
+                    if body := c.RawBody(); len(body) > 0 {
+                        return codec.Decode(body, o)
+                    }
+                    return nil
                 }
             }
 
-            // 其他情况（如 Form, Query 或断言失败）使用标准绑定
-            return gc.ShouldBindWith(o, b)
+            // 其他情况（如 Form, Multipart, Query）使用 gin 的标准绑定
+            return gc.ShouldBindWith(o, binding.Default(gc.Request.Method, gc.ContentType()))
         },
     } {
         if err = tryBind(f, value); err != nil {
@@ -153,14 +194,35 @@ func bindV3(c *Ctx, t reflect.Type, ptr bool) (_ any, err error) {
             return
         }
 
+        var trans ut.Translator
         if tr := c.engine.translator; tr != nil {
-            locale := c.locale().String() // 获取当前请求的语言
-            if trans, found := tr.GetTranslator(locale); found {
-                err = errors.New(errs[0].Translate(trans)) // 翻译首个校验错误
+            trans, _ = tr.GetTranslator(c.locale().String()) // 获取当前请求的语言
+        }
+
+        verr := &ValidationError{Errors: make([]FieldError, 0, len(errs))}
+        for _, fe := range errs {
+            msg := fe.Error()
+            if trans != nil {
+                msg = fe.Translate(trans) // 翻译校验错误
             }
+            verr.Errors = append(verr.Errors, FieldError{Field: fe.Field(), Rule: fe.Tag(), Message: msg})
         }
 
-        return
+        return nil, verr
+    }
+
+    // 分页参数填充默认值并校验最大数量
+    if q, ok := value.(pageQuery); ok {
+        if err = q.normalizePage(&c.engine.page); err != nil {
+            return
+        }
+    }
+
+    // 解析并校验排序、过滤和字段选择参数
+    if q, ok := value.(listQuery); ok {
+        if err = q.parseList(c); err != nil {
+            return
+        }
     }
 
     if ptr { // 用户要 *t
@@ -2,20 +2,27 @@ package sgin
 
 // MIME types that are commonly used
 const (
-	MIMETextHTML       = "text/html"
-	MIMETextPlain      = "text/plain"
-	MIMETextXML        = "text/xml"
-	MIMETextJavaScript = "text/javascript"
-	MIMETextCSS        = "text/css"
-	MIMEYAML           = "application/yaml"
-	MIMEYAMLX          = "application/x-yaml"
-	MIMEXML            = "application/xml"
-	MIMETOML           = "application/toml"
-	MIMEJSON           = "application/json"
-	MIMEJavaScript     = "application/javascript"
-	MIMEForm           = "application/x-www-form-urlencoded"
-	MIMEOctetStream    = "application/octet-stream"
-	MIMEMultipartForm  = "multipart/form-data"
+	MIMETextHTML        = "text/html"
+	MIMETextPlain       = "text/plain"
+	MIMETextXML         = "text/xml"
+	MIMETextJavaScript  = "text/javascript"
+	MIMETextCSS         = "text/css"
+	MIMECSV             = "text/csv"
+	MIMETextEventStream = "text/event-stream"
+	MIMEYAML            = "application/yaml"
+	MIMEYAMLX           = "application/x-yaml"
+	MIMEXML             = "application/xml"
+	MIMETOML            = "application/toml"
+	MIMEJSON            = "application/json"
+	MIMEProblemJSON     = "application/problem+json"
+	MIMEProblemXML      = "application/problem+xml"
+	MIMENDJSON          = "application/x-ndjson"
+	MIMEProtobuf        = "application/protobuf"
+	MIMEProtobufX       = "application/x-protobuf"
+	MIMEJavaScript      = "application/javascript"
+	MIMEForm            = "application/x-www-form-urlencoded"
+	MIMEOctetStream     = "application/octet-stream"
+	MIMEMultipartForm   = "multipart/form-data"
 )
 
 // HTTP Headers were copied from net/http.
@@ -0,0 +1,51 @@
+package sgin
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testEngine 创建测试模式的引擎
+func testEngine(cfg ...Config) *Engine {
+	c := Config{}
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+	if c.Mode == "" {
+		c.Mode = gin.TestMode
+	}
+	return New(c)
+}
+
+// serve 执行请求，headers 为成对的请求头名称和值。
+func serve(e *Engine, method, target string, body io.Reader, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, body)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	e.Gin().ServeHTTP(w, req)
+	return w
+}
+
+// mustPanic 断言 f 发生 panic 并返回 panic 的值
+func mustPanic(t *testing.T, f func()) (v any) {
+	t.Helper()
+	defer func() {
+		if v = recover(); v == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	f()
+	return
+}
+
+func expectStatus(t *testing.T, w *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("status = %d, want %d, body: %s", w.Code, status, w.Body.String())
+	}
+}
@@ -0,0 +1,83 @@
+// docsassets 下载 ui/assets.json 中记录的文档渲染器静态资源到 ui 目录，
+// 并为每个文件生成 .gz 预压缩版本，供 sgin 通过 embed.FS 内嵌。
+//
+// 在模块根目录执行：
+//
+//	go generate
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	if err := run("ui"); err != nil {
+		fmt.Fprintln(os.Stderr, "docsassets:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir string) error {
+	data, err := os.ReadFile(filepath.Join(dir, "assets.json"))
+	if err != nil {
+		return err
+	}
+
+	var manifest map[string]map[string]string
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return err
+	}
+
+	for renderer, files := range manifest {
+		for name, url := range files {
+			dst := filepath.Join(dir, renderer, name)
+			if err = download(url, dst); err != nil {
+				return fmt.Errorf("%s: %w", url, err)
+			}
+			fmt.Println(dst)
+		}
+	}
+	return nil
+}
+
+// download 将 url 的内容写入 dst 和 dst.gz
+func download(url, dst string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(dst, body, 0o644); err != nil {
+		return err
+	}
+
+	f, err := os.Create(dst + ".gz")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zw, err := gzip.NewWriterLevel(f, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err = zw.Write(body); err != nil {
+		return err
+	}
+	return zw.Close()
+}
@@ -0,0 +1,130 @@
+package sgin
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/bytedance/sonic"
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// JSONEncoder 是 JSON 编码器，*json.Encoder 和 sonic.Encoder 均满足该接口。
+type JSONEncoder interface {
+	Encode(v any) error
+	SetEscapeHTML(on bool)
+}
+
+// JSONDecoder 是 JSON 解码器，*json.Decoder 和 sonic.Decoder 均满足该接口。
+type JSONDecoder interface {
+	Decode(v any) error
+	UseNumber()
+	DisallowUnknownFields()
+}
+
+// JSONAPI 是引擎使用的 JSON 实现，可以是 StdJSON、SonicJSON 或自定义实现。
+type JSONAPI interface {
+	NewEncoder(w io.Writer) JSONEncoder
+	NewDecoder(r io.Reader) JSONDecoder
+}
+
+var (
+	StdJSON   JSONAPI = stdJSON{}   // 标准库 encoding/json
+	SonicJSON JSONAPI = sonicJSON{} // bytedance/sonic (与标准库兼容的配置)
+)
+
+type stdJSON struct{}
+
+func (stdJSON) NewEncoder(w io.Writer) JSONEncoder { return json.NewEncoder(w) }
+func (stdJSON) NewDecoder(r io.Reader) JSONDecoder { return json.NewDecoder(r) }
+
+type sonicJSON struct{}
+
+func (sonicJSON) NewEncoder(w io.Writer) JSONEncoder { return sonic.ConfigStd.NewEncoder(w) }
+func (sonicJSON) NewDecoder(r io.Reader) JSONDecoder { return sonic.ConfigStd.NewDecoder(r) }
+
+// JSONConfig 定义请求绑定、响应渲染、Params 和日志共用的 JSON 配置
+type JSONConfig struct {
+	API                   JSONAPI // JSON 实现，默认为 StdJSON。
+	UseNumber             bool    // 将请求体中 any 类型字段的数字解码为 json.Number，Params 始终使用 json.Number。
+	DisallowUnknownFields bool    // 请求体包含未知字段时绑定失败
+	EscapeHTML            bool    // 响应编码时转义 HTML 字符 (<、> 和 &)，日志始终不转义。
+}
+
+// DefaultJSONConfig 返回默认的 JSON 配置：响应转义 HTML 字符，请求体中的数字解码为 float64。
+func DefaultJSONConfig() JSONConfig {
+	return JSONConfig{
+		API:        StdJSON,
+		EscapeHTML: true,
+	}
+}
+
+// jsonCodec 按 JSONConfig 编解码 JSON，并兼容 binding.EnableDecoderXXX 选项。
+// proto.Message 使用 protojson 编解码，字段名与 OpenAPI 文档一致 (取自 json 标签)。
+type jsonCodec struct {
+	cfg JSONConfig
+}
+
+func newJSONCodec(cfg JSONConfig) *jsonCodec {
+	if cfg.API == nil {
+		cfg.API = StdJSON
+	}
+	return &jsonCodec{cfg: cfg}
+}
+
+func (j *jsonCodec) Decode(data []byte, v any) error {
+	return j.decode(data, v, j.cfg.UseNumber || binding.EnableDecoderUseNumber)
+}
+
+func (j *jsonCodec) decode(data []byte, v any, useNumber bool) error {
+	disallowUnknown := j.cfg.DisallowUnknownFields || binding.EnableDecoderDisallowUnknownFields
+
+	if m, ok := v.(proto.Message); ok {
+		return protojson.UnmarshalOptions{DiscardUnknown: !disallowUnknown}.Unmarshal(data, m)
+	}
+
+	dec := j.cfg.API.NewDecoder(bytes.NewReader(data))
+	if useNumber {
+		dec.UseNumber()
+	}
+	if disallowUnknown {
+		dec.DisallowUnknownFields()
+	}
+	return dec.Decode(v)
+}
+
+func (j *jsonCodec) Encode(w io.Writer, v any) error {
+	b, err := j.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// Marshal 返回 v 的 JSON 编码 (不含末尾换行)
+func (j *jsonCodec) Marshal(v any) ([]byte, error) {
+	return j.marshal(v, j.cfg.EscapeHTML)
+}
+
+// marshalLog 返回日志使用的 JSON 编码，不转义 HTML 字符以保持可读。
+func (j *jsonCodec) marshalLog(v any) ([]byte, error) {
+	return j.marshal(v, false)
+}
+
+func (j *jsonCodec) marshal(v any, escapeHTML bool) ([]byte, error) {
+	if m, ok := asProto(v); ok {
+		return protojson.MarshalOptions{UseProtoNames: true}.Marshal(m)
+	}
+
+	var buf bytes.Buffer
+	enc := j.cfg.API.NewEncoder(&buf)
+	enc.SetEscapeHTML(escapeHTML)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
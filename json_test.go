@@ -0,0 +1,87 @@
+package sgin
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type jsonBody struct {
+	Value any `json:"value" default:"1"`
+}
+
+// countingJSON 记录解码次数的 JSON 实现
+type countingJSON struct {
+	decodes int
+}
+
+func (j *countingJSON) NewEncoder(w io.Writer) JSONEncoder { return json.NewEncoder(w) }
+func (j *countingJSON) NewDecoder(r io.Reader) JSONDecoder {
+	j.decodes++
+	return json.NewDecoder(r)
+}
+
+func jsonEngine(f func(*JSONConfig)) *Engine {
+	e := testEngine(Config{JSON: f})
+	e.GET("/html", H(func(c *Ctx, _ struct{}) (string, error) {
+		return "<b>&</b>", nil
+	}))
+	e.POST("/bind", H(func(c *Ctx, in jsonBody) (string, error) {
+		return reflect.TypeOf(in.Value).String(), nil
+	}))
+	e.POST("/params", H(func(c *Ctx, _ struct{}) (string, error) {
+		return reflect.TypeOf(c.Params()["value"]).String(), nil
+	}))
+	return e
+}
+
+func TestJSONDefaults(t *testing.T) {
+	e := jsonEngine(nil)
+
+	if w := serve(e, http.MethodGet, "/html", nil); w.Body.String() != `"\u003cb\u003e\u0026\u003c/b\u003e"` {
+		t.Fatalf("body = %s", w.Body.String())
+	}
+
+	body := func() io.Reader { return strings.NewReader(`{"value":1}`) }
+	if w := serve(e, http.MethodPost, "/bind", body(), HeaderContentType, MIMEJSON); w.Body.String() != `"float64"` {
+		t.Fatalf("bind type = %s", w.Body.String())
+	}
+	if w := serve(e, http.MethodPost, "/params", body(), HeaderContentType, MIMEJSON); w.Body.String() != `"json.Number"` {
+		t.Fatalf("params type = %s", w.Body.String())
+	}
+
+	b, _ := newJSONCodec(DefaultJSONConfig()).marshalLog(map[string]string{"path": "/a?b=1&c=<d>"})
+	if string(b) != `{"path":"/a?b=1&c=<d>"}` {
+		t.Fatalf("log = %s", b)
+	}
+}
+
+func TestJSONConfig(t *testing.T) {
+	e := jsonEngine(func(c *JSONConfig) {
+		c.UseNumber, c.EscapeHTML = true, false
+	})
+
+	if w := serve(e, http.MethodGet, "/html", nil); w.Body.String() != `"<b>&</b>"` {
+		t.Fatalf("body = %s", w.Body.String())
+	}
+	w := serve(e, http.MethodPost, "/bind", strings.NewReader(`{"value":1}`), HeaderContentType, MIMEJSON)
+	if w.Body.String() != `"json.Number"` {
+		t.Fatalf("bind type = %s", w.Body.String())
+	}
+}
+
+func TestJSONTagValuesUseEngineCodec(t *testing.T) {
+	api := &countingJSON{}
+	e := testEngine(Config{OpenAPI: NewAPI(), JSON: func(c *JSONConfig) { c.API = api }})
+	s := structSchema(t, e.Spec().Components.Schemas, reflect.TypeFor[jsonBody]())
+
+	if api.decodes == 0 {
+		t.Fatal("default tag was not decoded with the engine codec")
+	}
+	if v := s.Properties["value"].Default; v != float64(1) {
+		t.Fatalf("default = %#v", v)
+	}
+}
@@ -2,10 +2,8 @@ package sgin
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
-	"github.com/bytedance/sonic"
 	"github.com/gin-gonic/gin"
 )
 
@@ -63,12 +61,8 @@ var Logger = Hn(func(c *Ctx) {
 			logMap["error"] = errMsg
 		}
 
-		var sb strings.Builder
-		enc := sonic.ConfigFastest.NewEncoder(&sb)
-		enc.SetEscapeHTML(false) // 禁止 HTML 转义
-		_ = enc.Encode(logMap)
-
-		fn(c, msg, sb.String())
+		b, _ := c.engine.json.marshalLog(logMap)
+		fn(c, msg, string(b)+"\n")
 		return
 	}
 
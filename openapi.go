@@ -2,9 +2,12 @@ package sgin
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"maps"
 	"regexp"
 	"slices"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -58,15 +61,25 @@ type PathItem struct {
 }
 
 type Operation struct {
-	Summary     string                   `yaml:"summary,omitempty"`
-	Description string                   `yaml:"description,omitempty"`
-	Parameters  []*Param                 `yaml:"parameters,omitempty"`
-	RequestBody *RequestBody             `yaml:"requestBody,omitempty"`
-	Responses   map[string]*ResponseBody `yaml:"responses,omitempty"`
-	Security    []Requirement            `yaml:"security,omitempty"`
-	Tags        []string                 `yaml:"tags,omitempty"`
+	Summary      string                   `yaml:"summary,omitempty"`
+	Description  string                   `yaml:"description,omitempty"`
+	ExternalDocs *ExternalDocumentation   `yaml:"externalDocs,omitempty"`
+	OperationID  string                   `yaml:"operationId,omitempty"` // 为空时由 API.OperationID 生成
+	Parameters   []*Param                 `yaml:"parameters,omitempty"`
+	RequestBody  *RequestBody             `yaml:"requestBody,omitempty"`
+	Responses    map[string]*ResponseBody `yaml:"responses,omitempty"`
+	Deprecated   bool                     `yaml:"deprecated,omitempty"`
+	Security     []Requirement            `yaml:"security,omitempty"`
+	Tags         []string                 `yaml:"tags,omitempty"`
+	Extensions   map[string]any           `yaml:",inline"` // 规范扩展，键名以 "x-" 开头。
 
-	Hidden bool `yaml:"-"`
+	Hidden      bool `yaml:"-"`
+	RawResponse bool `yaml:"-"` // 输出和错误不经过统一响应包装
+}
+
+type ExternalDocumentation struct {
+	Description string `yaml:"description,omitempty"`
+	URL         string `yaml:"url"`
 }
 
 type Param struct {
@@ -75,6 +88,8 @@ type Param struct {
 	In          string  `yaml:"in,omitempty"` // "query", "header", "path", "cookie"
 	Required    bool    `yaml:"required,omitempty"`
 	Description string  `yaml:"description,omitempty"`
+	Style       string  `yaml:"style,omitempty"` // "form", "deepObject" 等
+	Explode     *bool   `yaml:"explode,omitempty"`
 	Schema      *Schema `yaml:"schema,omitempty"`
 }
 
@@ -124,6 +139,81 @@ func (a *OpenAPI) YAML() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// JSON 返回 JSON 格式的 OpenAPI 规范，字段顺序与 YAML 保持一致。
+func (a *OpenAPI) JSON() ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(a); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := writeJSONNode(&buf, &node); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+// writeJSONNode 将 YAML 节点按顺序写为 JSON
+func writeJSONNode(buf *bytes.Buffer, n *yaml.Node) error {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) > 0 {
+			return writeJSONNode(buf, n.Content[0])
+		}
+		buf.WriteString("null")
+	case yaml.AliasNode:
+		return writeJSONNode(buf, n.Alias)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(n.Content[i].Value)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeJSONNode(buf, n.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range n.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONNode(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case yaml.ScalarNode:
+		switch n.ShortTag() {
+		case "!!null":
+			buf.WriteString("null")
+		case "!!bool", "!!int", "!!float":
+			if json.Valid([]byte(n.Value)) { // 排除 .inf、0x1F 等 JSON 不支持的写法
+				buf.WriteString(n.Value)
+				return nil
+			}
+			fallthrough
+		default:
+			value, _ := json.Marshal(n.Value)
+			buf.Write(value)
+		}
+	default:
+		return fmt.Errorf("sgin: unsupported yaml node kind %d", n.Kind)
+	}
+	return nil
+}
+
 // Clone 返回一份深度的 Operation 副本
 func (o *Operation) Clone() *Operation {
 	if o == nil {
@@ -133,7 +223,9 @@ func (o *Operation) Clone() *Operation {
 	clone := *o
 	clone.Parameters = slices.Clone(o.Parameters)
 	clone.Tags = slices.Clone(o.Tags)
+	clone.Security = slices.Clone(o.Security)
 	clone.Responses = maps.Clone(o.Responses)
+	clone.Extensions = maps.Clone(o.Extensions)
 
 	if clone.Responses == nil {
 		clone.Responses = map[string]*ResponseBody{}
@@ -147,6 +239,87 @@ func APIHidden(op *Operation) {
 	op.Hidden = true
 }
 
+// Summary 设置 Operation 的摘要
+func Summary(summary string) AddOperation {
+	return func(op *Operation) {
+		op.Summary = summary
+	}
+}
+
+// Description 设置 Operation 的描述，支持 Markdown。
+func Description(description string) AddOperation {
+	return func(op *Operation) {
+		op.Description = description
+	}
+}
+
+// Tags 为 Operation 添加标签，作用于路由组时组内的路由会继承这些标签。
+func Tags(tags ...string) AddOperation {
+	return func(op *Operation) {
+		for _, tag := range tags {
+			if !slices.Contains(op.Tags, tag) {
+				op.Tags = append(op.Tags, tag)
+			}
+		}
+	}
+}
+
+// Security 为 Operation 添加安全要求，scheme 为 Components.SecuritySchemes 中的名称 (如 "bearer")。
+// 多次调用表示满足任意一个即可，传入空名称表示允许匿名访问。
+func Security(scheme string, scopes ...string) AddOperation {
+	return func(op *Operation) {
+		if scheme == "" {
+			op.Security = append(op.Security, Requirement{})
+			return
+		}
+		if scopes == nil {
+			scopes = []string{}
+		}
+		op.Security = append(op.Security, Requirement{scheme: scopes})
+	}
+}
+
+// OperationID 设置 Operation 的唯一标识，客户端生成器通常以它作为方法名称。
+// 用于 Any 或 Match 注册的多个方法时，标识以方法名称为前缀，如 getX、postX；不能用于 Group。
+func OperationID(id string) AddOperation {
+	return func(op *Operation) {
+		op.OperationID = id
+	}
+}
+
+// Deprecated 将 Operation 标记为已弃用
+func Deprecated(op *Operation) {
+	op.Deprecated = true
+}
+
+// ExternalDocs 设置 Operation 的外部文档链接
+func ExternalDocs(url string, description ...string) AddOperation {
+	return func(op *Operation) {
+		op.ExternalDocs = &ExternalDocumentation{URL: url}
+		if len(description) > 0 {
+			op.ExternalDocs.Description = description[0]
+		}
+	}
+}
+
+// Extensions 为 Operation 添加规范扩展，键名缺少 "x-" 前缀时自动添加。
+func Extensions(extensions map[string]any) AddOperation {
+	return func(op *Operation) {
+		if op.Extensions == nil {
+			op.Extensions = map[string]any{}
+		}
+		for k, v := range extensions {
+			if !strings.HasPrefix(k, "x-") {
+				k = "x-" + k
+			}
+			op.Extensions[k] = v
+		}
+	}
+}
+
+// DocsHTML 是从 unpkg 加载 Stoplight Elements 的文档页面。
+//
+// Deprecated: 文档页面由 Config.Docs 配置，默认使用内嵌的静态资源，引擎不再使用该常量。
 const DocsHTML = `
 <!doctype html>
 <html lang="zh">
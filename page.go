@@ -0,0 +1,204 @@
+package sgin
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PageConfig 定义分页查询的配置
+type PageConfig struct {
+	DefaultSize int // 未指定 size 时的每页数量，默认 20。
+	MaxSize     int // 允许的最大每页数量，超过时返回 400 校验错误，默认 100。
+}
+
+// DefaultPageConfig 返回默认的分页配置
+func DefaultPageConfig() PageConfig {
+	return PageConfig{DefaultSize: 20, MaxSize: 100}
+}
+
+// PageQuery 是基于页码的分页参数，可以内嵌到处理器的输入结构中。
+type PageQuery struct {
+	Page int `form:"page" binding:"omitempty,min=1" default:"1" doc:"页码，从 1 开始。"`
+	Size int `form:"size" binding:"omitempty,min=1" doc:"每页数量"`
+}
+
+// Offset 返回当前页第一条数据的偏移量
+func (q *PageQuery) Offset() int {
+	return (q.Page - 1) * q.Size
+}
+
+// Limit 返回每页数量
+func (q *PageQuery) Limit() int {
+	return q.Size
+}
+
+func (q *PageQuery) normalizePage(cfg *PageConfig) error {
+	if q.Page <= 0 {
+		q.Page = 1
+	}
+	return normalizeSize(&q.Size, cfg)
+}
+
+// CursorQuery 是基于游标的分页参数，可以内嵌到处理器的输入结构中。
+type CursorQuery struct {
+	Cursor string `form:"cursor" doc:"分页游标，为空时从第一页开始。"`
+	Size   int    `form:"size" binding:"omitempty,min=1" doc:"每页数量"`
+}
+
+// Limit 返回每页数量
+func (q *CursorQuery) Limit() int {
+	return q.Size
+}
+
+func (q *CursorQuery) normalizePage(cfg *PageConfig) error {
+	return normalizeSize(&q.Size, cfg)
+}
+
+// pageQueryTypes 是包含 size 参数的分页参数类型
+var pageQueryTypes = map[reflect.Type]bool{
+	reflect.TypeFor[PageQuery]():   true,
+	reflect.TypeFor[CursorQuery](): true,
+}
+
+// pageSizeSchema 在分页参数 size 的 Schema 中记录引擎配置的默认值和最大值
+func (a *API) pageSizeSchema(s *Schema) {
+	if a.page == nil || s == nil {
+		return
+	}
+	if a.page.DefaultSize > 0 {
+		s.Default = a.page.DefaultSize
+	}
+	if a.page.MaxSize > 0 {
+		maxSize := float64(a.page.MaxSize)
+		s.Maximum = &maxSize
+	}
+}
+
+// pageQuery 由 PageQuery 和 CursorQuery 实现，在参数绑定后填充默认值并校验最大数量。
+type pageQuery interface {
+	normalizePage(cfg *PageConfig) error
+}
+
+func normalizeSize(size *int, cfg *PageConfig) error {
+	if *size <= 0 {
+		*size = cfg.DefaultSize
+	}
+	if cfg.MaxSize > 0 && *size > cfg.MaxSize {
+		return &ValidationError{Errors: []FieldError{{
+			Field:   "size",
+			Rule:    "max",
+			Message: fmt.Sprintf("size must be %d or less", cfg.MaxSize),
+		}}}
+	}
+	return nil
+}
+
+// Page 是分页查询的结果，使用 NewPage 或 NewCursorPage 创建。
+// 响应时会根据当前请求的路径和查询参数生成 RFC 8288 Link 响应头 (first, prev, next, last)。
+type Page[T any] struct {
+	Items []T    `json:"items" doc:"当前页的数据"`
+	Total int    `json:"total,omitempty" doc:"总数 (游标分页时可能为空)"`
+	Page  int    `json:"page,omitempty" doc:"当前页码 (游标分页时为空)"`
+	Size  int    `json:"size" doc:"每页数量"`
+	Next  string `json:"next,omitempty" doc:"下一页的游标"`
+	Prev  string `json:"prev,omitempty" doc:"上一页的游标"`
+}
+
+// NewPage 创建基于页码的分页结果，total 为总数。
+func NewPage[T any](q PageQuery, items []T, total int) *Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return &Page[T]{Items: items, Total: total, Page: q.Page, Size: q.Size}
+}
+
+// NewCursorPage 创建基于游标的分页结果，next 和 prev 为空表示没有下一页或上一页。
+func NewCursorPage[T any](q CursorQuery, items []T, next, prev string) *Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return &Page[T]{Items: items, Size: q.Size, Next: next, Prev: prev}
+}
+
+// pager 由 Page 及其指针实现，用于生成 Link 响应头、统一响应包装中的 Count 和选择字段。
+type pager interface {
+	pageLinks(c *Ctx) []string
+	pageTotal() int
+	selectFields(sel *fieldSelection) any
+}
+
+// asPager 将 data 转换为 pager，Page 的值和非 nil 指针都可以转换。
+func asPager(data any) (pager, bool) {
+	p, ok := data.(pager)
+	if ok {
+		if v := reflect.ValueOf(data); v.Kind() == reflect.Ptr && v.IsNil() {
+			return nil, false
+		}
+	}
+	return p, ok
+}
+
+func (p Page[T]) pageTotal() int {
+	return p.Total
+}
+
+func (p Page[T]) selectFields(sel *fieldSelection) any {
+	items := make([]any, len(p.Items))
+	for i, item := range p.Items {
+		items[i] = projectFields(item, sel, false)
+	}
+	return &Page[any]{Items: items, Total: p.Total, Page: p.Page, Size: p.Size, Next: p.Next, Prev: p.Prev}
+}
+
+func (p Page[T]) pageLinks(c *Ctx) (links []string) {
+	query := c.Request.URL.Query()
+	link := func(rel, key, value string) {
+		q := maps.Clone(query)
+		q.Set(key, value)
+		if key == "page" {
+			q.Del("cursor")
+		} else {
+			q.Del("page")
+		}
+		u := url.URL{Path: c.Path(), RawQuery: q.Encode()}
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel))
+	}
+
+	if p.Page == 0 { // 游标分页
+		if p.Prev != "" {
+			link("prev", "cursor", p.Prev)
+		}
+		if p.Next != "" {
+			link("next", "cursor", p.Next)
+		}
+		return
+	}
+
+	last := 1
+	if p.Size > 0 && p.Total > 0 {
+		last = (p.Total + p.Size - 1) / p.Size
+	}
+
+	link("first", "page", "1")
+	if p.Page > 1 {
+		link("prev", "page", strconv.Itoa(min(p.Page-1, last)))
+	}
+	if p.Page < last {
+		link("next", "page", strconv.Itoa(p.Page+1))
+	}
+	link("last", "page", strconv.Itoa(last))
+	return
+}
+
+// writePageLinks 为分页结果写入 Link 响应头
+func (c *Ctx) writePageLinks(data any) {
+	if p, ok := asPager(data); ok {
+		if links := p.pageLinks(c); len(links) > 0 {
+			c.Header(HeaderLink, strings.Join(links, ", "))
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package sgin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type pageItem struct {
+	ID int `json:"id"`
+}
+
+type pageReq struct {
+	PageQuery
+	Sort string `form:"sort"`
+}
+
+type cursorReq struct {
+	CursorQuery
+}
+
+func pageItems(q PageQuery, total int) []pageItem {
+	var items []pageItem
+	for i := q.Offset(); i < min(q.Offset()+q.Limit(), total); i++ {
+		items = append(items, pageItem{ID: i + 1})
+	}
+	return items
+}
+
+func TestPageLinks(t *testing.T) {
+	e := testEngine()
+	e.GET("/items", H(func(c *Ctx, in pageReq) (*Page[pageItem], error) {
+		return NewPage(in.PageQuery, pageItems(in.PageQuery, 50), 50), nil
+	}))
+	e.GET("/cursor", H(func(c *Ctx, in cursorReq) (*Page[pageItem], error) {
+		return NewCursorPage[pageItem](in.CursorQuery, nil, "n1", in.Cursor), nil
+	}))
+
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{"/items?page=2&size=20&sort=id", []string{
+			`</items?page=1&size=20&sort=id>; rel="first"`,
+			`</items?page=1&size=20&sort=id>; rel="prev"`,
+			`</items?page=3&size=20&sort=id>; rel="next"`,
+			`</items?page=3&size=20&sort=id>; rel="last"`,
+		}},
+		{"/items", []string{ // 默认第 1 页，没有 prev。
+			`</items?page=1>; rel="first"`,
+			`</items?page=2>; rel="next"`,
+			`</items?page=3>; rel="last"`,
+		}},
+		{"/items?page=9&size=25", []string{ // 超出范围时 prev 指向最后一页
+			`</items?page=1&size=25>; rel="first"`,
+			`</items?page=2&size=25>; rel="prev"`,
+			`</items?page=2&size=25>; rel="last"`,
+		}},
+		{"/cursor?cursor=p1&page=3", []string{ // 游标分页移除 page 参数
+			`</cursor?cursor=p1>; rel="prev"`,
+			`</cursor?cursor=n1>; rel="next"`,
+		}},
+	}
+
+	for _, tt := range tests {
+		w := serve(e, http.MethodGet, tt.target, nil)
+		expectStatus(t, w, http.StatusOK)
+		if got := w.Header().Get(HeaderLink); got != strings.Join(tt.want, ", ") {
+			t.Errorf("%s: Link =\n%s\nwant\n%s", tt.target, got, strings.Join(tt.want, ", "))
+		}
+	}
+}
+
+func TestPageValue(t *testing.T) {
+	e := testEngine(Config{Envelope: func(*EnvelopeConfig) {}})
+	e.GET("/items", H(func(c *Ctx, in pageReq) (Page[pageItem], error) {
+		return *NewPage(in.PageQuery, pageItems(in.PageQuery, 50), 50), nil
+	}))
+	e.GET("/nil", H(func(c *Ctx, in pageReq) (*Page[pageItem], error) {
+		return nil, nil
+	}))
+
+	w := serve(e, http.MethodGet, "/items?page=2", nil)
+	expectStatus(t, w, http.StatusOK)
+	if !strings.Contains(w.Header().Get(HeaderLink), `</items?page=3>; rel="last"`) {
+		t.Fatalf("Link = %s", w.Header().Get(HeaderLink))
+	}
+	var r ResultOf[Page[pageItem]]
+	if err := json.Unmarshal(w.Body.Bytes(), &r); err != nil || r.Count != 50 || r.Data.Page != 2 || len(r.Data.Items) != 20 {
+		t.Fatalf("body = %s", w.Body.String())
+	}
+
+	w = serve(e, http.MethodGet, "/nil", nil)
+	expectStatus(t, w, http.StatusOK)
+	if w.Header().Get(HeaderLink) != "" {
+		t.Fatalf("nil page Link = %s", w.Header().Get(HeaderLink))
+	}
+}
+
+func TestNormalizeSize(t *testing.T) {
+	cfg := &PageConfig{DefaultSize: 20, MaxSize: 100}
+	for _, tt := range []struct{ in, want int }{{0, 20}, {-5, 20}, {1, 1}, {100, 100}} {
+		size := tt.in
+		if err := normalizeSize(&size, cfg); err != nil || size != tt.want {
+			t.Errorf("normalizeSize(%d) = %d, %v", tt.in, size, err)
+		}
+	}
+
+	size := 101
+	var ve *ValidationError
+	if err := normalizeSize(&size, cfg); !errors.As(err, &ve) || ve.Errors[0].Field != "size" || ve.Errors[0].Rule != "max" {
+		t.Fatalf("err = %v", err)
+	}
+	if size = 1000; normalizeSize(&size, &PageConfig{DefaultSize: 20}) != nil {
+		t.Fatal("MaxSize 0 must not limit the size")
+	}
+
+	e := testEngine(Config{Page: func(c *PageConfig) { c.DefaultSize, c.MaxSize = 10, 30 }})
+	e.GET("/items", H(func(c *Ctx, in pageReq) (*Page[pageItem], error) {
+		return NewPage[pageItem](in.PageQuery, nil, 0), nil
+	}))
+	expectStatus(t, serve(e, http.MethodGet, "/items?size=31", nil), http.StatusBadRequest)
+	if w := serve(e, http.MethodGet, "/items", nil); !strings.Contains(w.Body.String(), `"size":10`) {
+		t.Fatalf("body = %s", w.Body.String())
+	}
+}
+
+func TestPageSizeSchema(t *testing.T) {
+	e := testEngine(Config{
+		OpenAPI: NewAPI(),
+		Page:    func(c *PageConfig) { c.DefaultSize, c.MaxSize = 10, 30 },
+	})
+	e.GET("/items", H(func(c *Ctx, in pageReq) (*Page[pageItem], error) { return nil, nil }))
+	e.GET("/cursor", H(func(c *Ctx, in cursorReq) (*Page[pageItem], error) { return nil, nil }))
+
+	for _, path := range []string{"/items", "/cursor"} {
+		var size *Param
+		for _, p := range e.Spec().Paths[path].Get.Parameters {
+			if p.Name == "size" {
+				size = p
+			}
+		}
+		if size == nil || size.Schema.Maximum == nil || *size.Schema.Maximum != 30 || size.Schema.Default != 10 {
+			t.Fatalf("%s: size = %+v", path, size)
+		}
+	}
+}
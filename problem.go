@@ -0,0 +1,132 @@
+package sgin
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"reflect"
+)
+
+// FieldError 描述一个字段的校验错误
+type FieldError struct {
+	Field   string `json:"field" xml:"field"`                   // 字段名称
+	Rule    string `json:"rule,omitempty" xml:"rule,omitempty"` // 未通过的校验规则，如 "required"。
+	Message string `json:"message" xml:"message"`               // 校验错误消息 (已按请求语言翻译)
+}
+
+// ValidationError 是请求参数校验失败时传递给 ErrorHandler 的错误，
+// 它包装了一个 400 *Error，因此 errors.As(err, &e) 仍可获取状态码。
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Errors) == 0 {
+		return http.StatusText(http.StatusBadRequest)
+	}
+	return e.Errors[0].Message
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrBadRequest(e.Error())
+}
+
+// Problem 是 RFC 9457 定义的问题详情 (Problem Details)，也可以作为错误直接从处理器返回。
+type Problem struct {
+	XMLName  xml.Name       `json:"-" xml:"urn:ietf:rfc:7807 problem" yaml:"-"`
+	Type     string         `json:"type,omitempty" xml:"type,omitempty" doc:"问题类型的 URI"`
+	Title    string         `json:"title,omitempty" xml:"title,omitempty" doc:"问题类型的简短描述"`
+	Status   int            `json:"status,omitempty" xml:"status,omitempty" doc:"HTTP 状态码"`
+	Detail   string         `json:"detail,omitempty" xml:"detail,omitempty" doc:"本次问题的具体说明"`
+	Instance string         `json:"instance,omitempty" xml:"instance,omitempty" doc:"本次请求的跟踪 ID"`
+	Errors   []FieldError   `json:"errors,omitempty" xml:"errors>error,omitempty" doc:"参数校验错误"`
+	Reason   string         `json:"reason,omitempty" xml:"reason,omitempty" doc:"业务错误码"`
+	Details  map[string]any `json:"details,omitempty" xml:"-" doc:"错误详情"`
+}
+
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+var problemType = reflect.TypeFor[Problem]()
+
+// NewProblem 根据错误创建问题详情，instance 为请求的跟踪 ID。
+// 非 *Error 的 5xx 错误不会在 detail 中暴露内部错误信息。
+func NewProblem(c *Ctx, err error) *Problem {
+	var p *Problem
+	isProblem := errors.As(err, &p)
+	if isProblem {
+		clone := *p
+		p = &clone
+	} else {
+		p = &Problem{}
+	}
+
+	if p.Status == 0 {
+		p.Status = errorStatus(c, err)
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Title == "" {
+		p.Title = c.statusTitle(p.Status)
+	}
+	if p.Instance == "" {
+		p.Instance = c.TraceID()
+	}
+
+	var e *Error
+	isError := errors.As(err, &e)
+	if !isProblem && (isError || p.Status < 500) {
+		p.Detail = c.errorMessage(err)
+	}
+	if isError && !isProblem {
+		p.Reason, p.Details = e.Reason, e.Details
+	}
+
+	var verr *ValidationError
+	if p.Errors == nil && errors.As(err, &verr) {
+		p.Errors = verr.Errors
+	}
+
+	return p
+}
+
+// ProblemErrorHandler 以 RFC 9457 问题详情响应错误，
+// 根据 Accept 协商 application/problem+json (默认) 或 application/problem+xml。
+func ProblemErrorHandler(c *Ctx, err error) error {
+	p := NewProblem(c, err)
+	if p.Status >= 500 {
+		_ = c.ctx.Error(err) // 由 Logger 记录原始错误
+	}
+
+	writeErrorHeaders(c, err)
+	c.Status(p.Status)
+	switch c.ctx.NegotiateFormat(MIMEProblemJSON, MIMEJSON, MIMEProblemXML, MIMEXML, MIMETextXML) {
+	case MIMEProblemXML, MIMEXML, MIMETextXML:
+		c.Content(contentType(MIMEProblemXML)).encode("", xmlCodec{}, p)
+	default:
+		c.Content(contentType(MIMEProblemJSON)).encode("", c.engine.json, p)
+	}
+
+	return nil
+}
+
+// parseProblem 为 Operation 注入引用 Problem 的 default 错误响应
+func (a *API) parseProblem(op *Operation) {
+	if _, ok := op.Responses["default"]; ok {
+		return
+	}
+
+	schema := a.Schema(problemType)
+	op.Responses["default"] = &ResponseBody{
+		Description: "Problem Details (RFC 9457)",
+		Content: map[string]*MediaType{
+			MIMEProblemJSON: {Schema: schema},
+			MIMEProblemXML:  {Schema: schema},
+		},
+	}
+}
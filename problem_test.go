@@ -0,0 +1,61 @@
+package sgin
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestProblemErrorHandler(t *testing.T) {
+	e := testEngine(Config{ErrorHandler: ProblemErrorHandler})
+	e.GET("/", He(func(c *Ctx) error {
+		return ErrNotFound("no such user")
+	}))
+
+	w := serve(e, http.MethodGet, "/", nil)
+	expectStatus(t, w, http.StatusNotFound)
+	if ct := w.Header().Get(HeaderContentType); !strings.HasPrefix(ct, MIMEProblemJSON) {
+		t.Fatalf("content type = %s", ct)
+	}
+	if body := w.Body.String(); !strings.Contains(body, `"status":404`) || !strings.Contains(body, `"detail":"no such user"`) {
+		t.Fatalf("body = %s", body)
+	}
+
+	w = serve(e, http.MethodGet, "/", nil, HeaderAccept, MIMEProblemXML)
+	if ct := w.Header().Get(HeaderContentType); !strings.HasPrefix(ct, MIMEProblemXML) {
+		t.Fatalf("content type = %s", ct)
+	}
+}
+
+func TestProblemResponses(t *testing.T) {
+	on, off := true, false
+	wrapped := func(c *Ctx, err error) error { return ProblemErrorHandler(c, err) }
+	tests := []struct {
+		name     string
+		cfg      Config
+		override *bool
+		want     bool
+	}{
+		{"default", Config{}, nil, false},
+		{"problem", Config{Problem: true}, nil, true},                // 自动记录
+		{"opt-out", Config{Problem: true}, &off, false},              // 显式关闭
+		{"custom handler", Config{ErrorHandler: wrapped}, &on, true}, // 自定义处理器显式开启
+		{"problem with custom handler", Config{Problem: true, ErrorHandler: wrapped}, nil, true},
+	}
+
+	for _, tt := range tests {
+		tt.cfg.OpenAPI = NewAPI(func(a *API) { a.ProblemResponses = tt.override })
+		e := testEngine(tt.cfg)
+		e.GET("/", He(func(c *Ctx) error { return ErrNotFound() }))
+
+		if _, ok := e.Spec().Paths["/"].Get.Responses["default"]; ok != tt.want {
+			t.Errorf("%s: default response documented = %v", tt.name, ok)
+		}
+		if tt.cfg.Problem {
+			ct := serve(e, http.MethodGet, "/", nil).Header().Get(HeaderContentType)
+			if !strings.HasPrefix(ct, MIMEProblemJSON) {
+				t.Errorf("%s: content type = %s", tt.name, ct)
+			}
+		}
+	}
+}
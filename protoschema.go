@@ -0,0 +1,128 @@
+package sgin
+
+import (
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// isProto 报告类型 t 或其指针是否实现 proto.Message
+func isProto(t reflect.Type) bool {
+	return t != nil && protoCodec{}.Supports(t)
+}
+
+// bodySchema 返回请求体或响应体的 Schema。
+// proto.Message 作为整个消息体时使用 protojson 编解码，按 protojson 的映射生成 Schema，其他类型通过反射生成。
+func (r *Registry) bodySchema(t reflect.Type, hint ...string) *Schema {
+	if !isProto(t) {
+		return r.Schema(t, hint...)
+	}
+	if t.Kind() != reflect.Ptr {
+		t = reflect.PointerTo(t)
+	}
+	return r.protoMessage(reflect.New(t.Elem()).Interface().(proto.Message).ProtoReflect())
+}
+
+// protoMessage 返回消息 m 的 Schema，字段使用 proto 名称 (与 protojson.MarshalOptions.UseProtoNames 一致)。
+// 消息注册为以完整名称 (如 "example.v1.User") 命名的组件，与反射生成的 Go 结构体组件互不冲突；
+// 知名类型 (如 google.protobuf.Timestamp) 使用各自的 JSON 表示，不注册组件。
+func (r *Registry) protoMessage(m protoreflect.Message) *Schema {
+	md := m.Descriptor()
+	if s := protoWellKnown(r, md); s != nil {
+		return s
+	}
+
+	return r.derive(string(md.FullName()), md.FullName(), func() *Schema {
+		props := map[string]*Schema{}
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			props[string(fd.Name())] = r.protoField(m, fd)
+		}
+		return &Schema{Type: TypeObject, Properties: props}
+	})
+}
+
+// protoField 返回消息 m 中字段 fd 的 Schema
+func (r *Registry) protoField(m protoreflect.Message, fd protoreflect.FieldDescriptor) *Schema {
+	switch {
+	case fd.IsMap():
+		value := m.NewField(fd).Map().NewValue()
+		return &Schema{Type: TypeObject, AdditionalProperties: r.protoValue(fd.MapValue(), value)}
+	case fd.IsList():
+		return &Schema{Type: TypeArray, Items: r.protoValue(fd, m.NewField(fd).List().NewElement())}
+	}
+	return r.protoValue(fd, m.NewField(fd))
+}
+
+// protoValue 返回字段 fd 单个值的 Schema，v 用于获取消息字段的具体类型。
+// protojson 将 64 位整数编码为字符串，枚举编码为值的名称。
+func (r *Registry) protoValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) *Schema {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return &Schema{Type: TypeBoolean}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &Schema{Type: TypeInteger, Format: "int32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &Schema{Type: TypeString, Format: "int64"}
+	case protoreflect.FloatKind:
+		return &Schema{Type: TypeNumber, Format: "float"}
+	case protoreflect.DoubleKind:
+		return &Schema{Type: TypeNumber, Format: "double"}
+	case protoreflect.StringKind:
+		return &Schema{Type: TypeString}
+	case protoreflect.BytesKind:
+		return &Schema{Type: TypeString, ContentEncoding: "base64"}
+	case protoreflect.EnumKind:
+		ed := fd.Enum()
+		if ed.FullName() == "google.protobuf.NullValue" {
+			return &Schema{Type: "null"}
+		}
+		s := &Schema{Type: TypeString}
+		values := ed.Values()
+		for i := 0; i < values.Len(); i++ {
+			s.Enum = append(s.Enum, string(values.Get(i).Name()))
+		}
+		return s
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if v.IsValid() {
+			return r.protoMessage(v.Message())
+		}
+	}
+	return &Schema{}
+}
+
+// protoWellKnown 返回知名类型的 JSON 表示，其他消息返回 nil。
+func protoWellKnown(r *Registry, md protoreflect.MessageDescriptor) *Schema {
+	if md.ParentFile() == nil || md.ParentFile().Package() != "google.protobuf" {
+		return nil
+	}
+
+	switch md.Name() {
+	case "Timestamp":
+		return &Schema{Type: TypeString, Format: "date-time"}
+	case "Duration":
+		return &Schema{Type: TypeString, Pattern: `^-?\d+(\.\d+)?s$`, Examples: []any{"1.5s"}}
+	case "FieldMask":
+		return &Schema{Type: TypeString}
+	case "Struct", "Empty":
+		return &Schema{Type: TypeObject}
+	case "ListValue":
+		return &Schema{Type: TypeArray, Items: &Schema{}}
+	case "Value":
+		return &Schema{}
+	case "Any":
+		return &Schema{
+			Type:       TypeObject,
+			Properties: map[string]*Schema{"@type": {Type: TypeString}},
+			Required:   []string{"@type"},
+		}
+	case "DoubleValue", "FloatValue", "Int64Value", "UInt64Value", "Int32Value",
+		"UInt32Value", "BoolValue", "StringValue", "BytesValue": // 包装类型编码为其中的值
+		return r.protoValue(md.Fields().ByName("value"), protoreflect.Value{})
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package sgin
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func protoEngine() *Engine {
+	e := testEngine(Config{OpenAPI: NewAPI()})
+	e.GET("/time", H(func(c *Ctx, _ struct{}) (*timestamppb.Timestamp, error) {
+		return timestamppb.New(time.Unix(1700000000, 0)), nil
+	}))
+	e.POST("/field", H(func(c *Ctx, in *descriptorpb.FieldDescriptorProto) (*descriptorpb.FieldDescriptorProto, error) {
+		return in, nil
+	}))
+	e.GET("/option", H(func(c *Ctx, _ struct{}) (*descriptorpb.UninterpretedOption, error) {
+		return &descriptorpb.UninterpretedOption{NegativeIntValue: proto.Int64(-7)}, nil
+	}))
+	return e
+}
+
+func TestProtoSchemaMatchesProtoJSON(t *testing.T) {
+	e := protoEngine()
+	spec := e.Spec()
+	schemas := spec.Components.Schemas
+
+	// Timestamp 编码为 RFC 3339 字符串
+	w := serve(e, http.MethodGet, "/time", nil)
+	if w.Body.String() != `"2023-11-14T22:13:20Z"` {
+		t.Fatalf("body = %s", w.Body.String())
+	}
+	if s := spec.Paths["/time"].Get.Responses["200"].Content[MIMEJSON].Schema; s.Type != TypeString || s.Format != "date-time" {
+		t.Fatalf("timestamp schema = %+v", s)
+	}
+
+	// 枚举编码为名称，字段使用 proto 名称。
+	w = serve(e, http.MethodPost, "/field", strings.NewReader(`{"name":"id","type":"TYPE_INT64","json_name":"id"}`), HeaderContentType, MIMEJSON)
+	expectStatus(t, w, http.StatusOK)
+	var got map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil || got["type"] != "TYPE_INT64" || got["json_name"] != "id" {
+		t.Fatalf("body = %s", w.Body.String())
+	}
+
+	op := spec.Paths["/field"].Post
+	ref := op.Responses["200"].Content[MIMEJSON].Schema.Ref
+	if ref != schemas.Prefix+"google.protobuf.FieldDescriptorProto" || op.RequestBody.Content[MIMEJSON].Schema.Ref != ref {
+		t.Fatalf("request/response refs = %s, %s", op.RequestBody.Content[MIMEJSON].Schema.Ref, ref)
+	}
+	field := schemas.Ref(ref).Properties
+	if typ := field["type"]; typ.Type != TypeString || !slices.Contains(typ.Enum, any("TYPE_INT64")) {
+		t.Fatalf("enum schema = %+v", typ)
+	}
+	if field["number"].Type != TypeInteger || field["json_name"] == nil || field["jsonName"] != nil {
+		t.Fatalf("properties = %v", field)
+	}
+	if options := field["options"]; options.Ref != schemas.Prefix+"google.protobuf.FieldOptions" {
+		t.Fatalf("options = %+v", options)
+	}
+
+	// 64 位整数编码为字符串
+	w = serve(e, http.MethodGet, "/option", nil)
+	if w.Body.String() != `{"negative_int_value":"-7"}` {
+		t.Fatalf("body = %s", w.Body.String())
+	}
+	option := schemas.Ref(schemas.Prefix + "google.protobuf.UninterpretedOption").Properties
+	if s := option["negative_int_value"]; s.Type != TypeString || s.Format != "int64" {
+		t.Fatalf("int64 schema = %+v", s)
+	}
+	if s := option["name"]; s.Type != TypeArray || s.Items.Ref != schemas.Prefix+"google.protobuf.UninterpretedOption.NamePart" {
+		t.Fatalf("repeated message schema = %+v", s)
+	}
+}
+
+func TestProtoSchemaRecursive(t *testing.T) {
+	r := NewAPI().Components.Schemas
+	s := r.bodySchema(reflect.TypeFor[*descriptorpb.DescriptorProto]())
+
+	props := r.Ref(s.Ref).Properties
+	if nested := props["nested_type"]; nested.Items.Ref != s.Ref {
+		t.Fatalf("nested_type = %+v", nested.Items)
+	}
+
+	// 包装类型编码为其中的值，反射生成的 Go 结构体组件不受影响。
+	if s := r.bodySchema(reflect.TypeFor[*wrapperspb.Int64Value]()); s.Type != TypeString || s.Format != "int64" {
+		t.Fatalf("Int64Value = %+v", s)
+	}
+	if s := r.Schema(reflect.TypeFor[codecUser]()); r.Ref(s.Ref).Properties["name"] == nil {
+		t.Fatalf("struct schema = %+v", r.Ref(s.Ref))
+	}
+}
+
+func TestProtoMediaTypes(t *testing.T) {
+	e := protoEngine()
+
+	content := e.Spec().Paths["/field"].Post.Responses["200"].Content
+	var media []string
+	for m := range content {
+		media = append(media, m)
+	}
+	slices.Sort(media)
+	if !slices.Equal(media, []string{MIMEJSON, MIMEProtobufX}) {
+		t.Fatalf("documented media types = %v", media)
+	}
+
+	for _, accept := range []string{MIMEXML, MIMEYAML, MIMETOML} {
+		expectStatus(t, serve(e, http.MethodGet, "/time", nil, HeaderAccept, accept), http.StatusNotAcceptable)
+	}
+	expectStatus(t, serve(e, http.MethodPost, "/field", strings.NewReader(`<name>id</name>`), HeaderContentType, MIMEXML), http.StatusUnsupportedMediaType)
+
+	w := serve(e, http.MethodGet, "/time", nil, HeaderAccept, MIMEProtobufX)
+	expectStatus(t, w, http.StatusOK)
+	var ts timestamppb.Timestamp
+	if err := proto.Unmarshal(w.Body.Bytes(), &ts); err != nil || ts.Seconds != 1700000000 {
+		t.Fatalf("protobuf body = %v, %v", &ts, err)
+	}
+}
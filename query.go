@@ -0,0 +1,320 @@
+package sgin
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/baagod/sgin/v2/helper"
+)
+
+const fieldsKey = "_baa/sgin/fields"
+
+var xmlNameType = reflect.TypeFor[xml.Name]()
+
+// ListQuery 是列表查询的排序、过滤和字段选择参数，可以内嵌到处理器的输入结构中。
+// T 为列表元素的类型，允许的字段通过 T 的结构体标签声明：
+//
+//	type User struct {
+//	    ID      int       `json:"id" sortable:"true"`
+//	    Status  string    `json:"status" filterable:"true" enum:"open,closed"`
+//	    Created time.Time `json:"created" sortable:"true"`
+//	}
+//
+// 支持的查询参数为 sort=-created,id、filter[status]=open 和 fields=id,status，
+// 未声明的字段返回 400 校验错误。选择的字段会在响应时自动应用。
+type ListQuery[T any] struct {
+	Sort   Sort[T]   `form:"-"`
+	Filter Filter[T] `form:"-"`
+	Fields Fields[T] `form:"-"`
+}
+
+// SortField 是一个排序字段
+type SortField struct {
+	Field string // JSON 字段名称
+	Desc  bool   // 是否降序
+}
+
+// Sort 是 sort 查询参数解析后的排序字段列表
+type Sort[T any] []SortField
+
+// Filter 是 filter[字段] 查询参数解析后的过滤条件，键为 JSON 字段名称。
+type Filter[T any] map[string]string
+
+// Fields 是 fields 查询参数解析后的字段列表，为空时返回所有字段。
+type Fields[T any] []string
+
+// listQuery 由 ListQuery 实现，在参数绑定后解析并校验查询参数。
+type listQuery interface {
+	parseList(c *Ctx) error
+}
+
+func (q *ListQuery[T]) parseList(c *Ctx) error {
+	fields := queryFields(reflect.TypeFor[T]())
+	query := c.Request.URL.Query()
+
+	if v := query.Get("sort"); v != "" {
+		for name := range strings.SplitSeq(v, ",") {
+			var sf SortField
+			sf.Field, sf.Desc = strings.CutPrefix(strings.TrimPrefix(name, "+"), "-")
+			if f, ok := fields[sf.Field]; !ok || f.Tag.Get("sortable") != "true" {
+				return queryError("sort", fmt.Sprintf("sort field %q is not allowed", sf.Field))
+			}
+			q.Sort = append(q.Sort, sf)
+		}
+	}
+
+	for key, values := range query {
+		name, ok := strings.CutPrefix(key, "filter[")
+		if !ok || !strings.HasSuffix(name, "]") {
+			continue
+		}
+
+		name = strings.TrimSuffix(name, "]")
+		f, ok := fields[name]
+		if !ok || f.Tag.Get("filterable") != "true" {
+			return queryError("filter", fmt.Sprintf("filter field %q is not allowed", name))
+		}
+		if !validFilterValue(f, values[0]) {
+			return queryError("filter", fmt.Sprintf("invalid value %q for filter field %q", values[0], name))
+		}
+
+		if q.Filter == nil {
+			q.Filter = Filter[T]{}
+		}
+		q.Filter[name] = values[0]
+	}
+
+	if v := query.Get("fields"); v != "" {
+		for name := range strings.SplitSeq(v, ",") {
+			if _, ok := fields[name]; !ok {
+				return queryError("fields", fmt.Sprintf("field %q does not exist", name))
+			}
+			q.Fields = append(q.Fields, name)
+		}
+		c.Get(fieldsKey, &fieldSelection{typ: helper.Deref(reflect.TypeFor[T]()), fields: q.Fields}) // 响应时只保留选择的字段
+	}
+
+	return nil
+}
+
+func queryError(field, msg string) error {
+	return &ValidationError{Errors: []FieldError{{Field: field, Rule: "oneof", Message: msg}}}
+}
+
+// queryFields 返回类型 t (结构体或结构体指针) 以 JSON 名称为键的可见字段
+func queryFields(t reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	if t = helper.Deref(t); t.Kind() != reflect.Struct {
+		return fields
+	}
+
+	for _, f := range reflect.VisibleFields(t) {
+		if f.Anonymous || !f.IsExported() {
+			continue
+		}
+		if name := jsonName(f); name != "-" {
+			fields[name] = f
+		}
+	}
+	return fields
+}
+
+// jsonName 返回字段的 JSON 名称
+func jsonName(f reflect.StructField) string {
+	if name := strings.Split(f.Tag.Get("json"), ",")[0]; name != "" {
+		return name
+	}
+	return f.Name
+}
+
+// validFilterValue 检查过滤值是否符合字段的 enum 标签和类型
+func validFilterValue(f reflect.StructField, v string) bool {
+	if enum := f.Tag.Get("enum"); enum != "" {
+		return slices.Contains(strings.Split(enum, ","), v)
+	}
+
+	var err error
+	switch helper.Deref(f.Type).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, err = strconv.ParseInt(v, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		_, err = strconv.ParseUint(v, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		_, err = strconv.ParseFloat(v, 64)
+	case reflect.Bool:
+		_, err = strconv.ParseBool(v)
+	}
+	return err == nil
+}
+
+// queryParam 由 Sort、Filter 和 Fields 实现，用于在文档中描述查询参数。
+type queryParam interface {
+	openAPIParam(a *API) *Param
+}
+
+var explodeFalse = false
+
+func (Sort[T]) openAPIParam(*API) *Param {
+	var enum []any
+	for _, f := range sortedFields(reflect.TypeFor[T]()) {
+		if f.Tag.Get("sortable") == "true" {
+			enum = append(enum, jsonName(f), "-"+jsonName(f))
+		}
+	}
+
+	return &Param{
+		Name:        "sort",
+		In:          "query",
+		Description: "排序字段，以逗号分隔，\"-\" 前缀表示降序。",
+		Style:       "form",
+		Explode:     &explodeFalse,
+		Schema:      &Schema{Type: TypeArray, Items: &Schema{Type: TypeString, Enum: enum}},
+	}
+}
+
+func (Filter[T]) openAPIParam(a *API) *Param {
+	props := map[string]*Schema{}
+	for _, f := range sortedFields(reflect.TypeFor[T]()) {
+		if f.Tag.Get("filterable") == "true" {
+			props[jsonName(f)] = a.Field(f, f.Name)
+		}
+	}
+
+	return &Param{
+		Name:        "filter",
+		In:          "query",
+		Description: "过滤条件，如 filter[status]=open。",
+		Style:       "deepObject",
+		Schema:      &Schema{Type: TypeObject, Properties: props},
+	}
+}
+
+func (Fields[T]) openAPIParam(*API) *Param {
+	var enum []any
+	for _, f := range sortedFields(reflect.TypeFor[T]()) {
+		enum = append(enum, jsonName(f))
+	}
+
+	return &Param{
+		Name:        "fields",
+		In:          "query",
+		Description: "返回的字段，以逗号分隔，为空时返回所有字段。",
+		Style:       "form",
+		Explode:     &explodeFalse,
+		Schema:      &Schema{Type: TypeArray, Items: &Schema{Type: TypeString, Enum: enum}},
+	}
+}
+
+// sortedFields 按声明顺序返回 queryFields 中的字段
+func sortedFields(t reflect.Type) []reflect.StructField {
+	fields := make([]reflect.StructField, 0)
+	for _, f := range queryFields(t) {
+		fields = append(fields, f)
+	}
+	slices.SortFunc(fields, func(a, b reflect.StructField) int {
+		return slices.Compare(a.Index, b.Index)
+	})
+	return fields
+}
+
+// fieldSelection 是 fields 查询参数选择的字段，只作用于 ListQuery 的元素类型。
+type fieldSelection struct {
+	typ    reflect.Type
+	fields []string
+	proj   [2]*projection // 缓存的投影，以 root 区分。
+}
+
+// projection 返回元素类型的投影，同一请求中只计算一次。
+func (sel *fieldSelection) projection(root bool) *projection {
+	i := 0
+	if root {
+		i = 1
+	}
+	if sel.proj[i] == nil {
+		sel.proj[i] = newProjection(sel.typ, sel.fields, root)
+	}
+	return sel.proj[i]
+}
+
+// selectFields 只保留 data 中选择的字段，支持元素类型的结构体、结构体列表和 Page。
+func selectFields(data any, sel *fieldSelection) any {
+	if p, ok := asPager(data); ok {
+		return p.selectFields(sel)
+	}
+	return projectFields(data, sel, true)
+}
+
+// projectFields 返回只包含选择字段的值，root 为 true 时保留原类型的 XML 元素名称。
+func projectFields(data any, sel *fieldSelection, root bool) any {
+
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return data
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == sel.typ {
+			return sel.projection(root).value(v).Interface()
+		}
+	case reflect.Slice, reflect.Array:
+		if helper.Deref(v.Type().Elem()) != sel.typ {
+			return data
+		}
+		p := sel.projection(root)
+		out := reflect.MakeSlice(reflect.SliceOf(p.typ), v.Len(), v.Len())
+		for i := range v.Len() {
+			if e := reflect.Indirect(v.Index(i)); e.IsValid() {
+				out.Index(i).Set(p.value(e))
+			}
+		}
+		return out.Interface()
+	}
+
+	return data
+}
+
+// projection 是只包含选择字段的结构体类型，index 为各字段 (XMLName 之后) 在原类型中的索引。
+type projection struct {
+	typ   reflect.Type
+	index [][]int
+}
+
+// newProjection 返回类型 t 中 fields 指定字段的投影，投影类型的第一个字段为 XMLName。
+func newProjection(t reflect.Type, fields []string, root bool) *projection {
+	xmlName := reflect.StructField{Name: "XMLName", Type: xmlNameType, Tag: `json:"-" yaml:"-" toml:"-" xml:"-"`}
+	if f, ok := t.FieldByName("XMLName"); ok && f.Type == xmlNameType {
+		xmlName.Tag = f.Tag
+	} else if root { // 匿名结构体没有类型名称，需要显式指定根元素。
+		xmlName.Tag = reflect.StructTag(`json:"-" yaml:"-" toml:"-" xml:"` + typeName(t) + `"`)
+	}
+
+	p := &projection{}
+	out := []reflect.StructField{xmlName}
+	for _, f := range sortedFields(t) {
+		if f.Name != "XMLName" && slices.Contains(fields, jsonName(f)) {
+			out = append(out, reflect.StructField{Name: f.Name, Type: f.Type, Tag: f.Tag})
+			p.index = append(p.index, f.Index)
+		}
+	}
+	p.typ = reflect.StructOf(out)
+	return p
+}
+
+// value 返回 v 的投影，经过 nil 内嵌指针的字段保留零值。
+func (p *projection) value(v reflect.Value) reflect.Value {
+	out := reflect.New(p.typ).Elem()
+	for i, index := range p.index {
+		if f, err := v.FieldByIndexErr(index); err == nil {
+			out.Field(i + 1).Set(f) // 跳过 XMLName
+		}
+	}
+	return out
+}
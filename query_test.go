@@ -0,0 +1,64 @@
+package sgin
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type queryBase struct {
+	Name string `json:"name"`
+}
+
+type queryItem struct {
+	ID     int    `json:"id" sortable:"true"`
+	Status string `json:"status" filterable:"true" enum:"open,closed"`
+	*queryBase
+}
+
+type queryReq struct {
+	ListQuery[queryItem]
+}
+
+func queryEngine(items []queryItem) *Engine {
+	e := testEngine()
+	e.GET("/items", H(func(c *Ctx, in queryReq) ([]queryItem, error) {
+		return items, nil
+	}))
+	e.GET("/page", H(func(c *Ctx, in queryReq) (*Page[queryItem], error) {
+		return &Page[queryItem]{Items: items, Total: len(items)}, nil
+	}))
+	return e
+}
+
+func TestQueryFields(t *testing.T) {
+	items := []queryItem{{ID: 1, Status: "open", queryBase: &queryBase{Name: "a"}}, {ID: 2}}
+	e := queryEngine(items)
+
+	w := serve(e, http.MethodGet, "/items?fields=id,name", nil)
+	expectStatus(t, w, http.StatusOK)
+	if body := w.Body.String(); body != `[{"id":1,"name":"a"},{"id":2,"name":""}]` {
+		t.Fatalf("body = %s", body)
+	}
+
+	w = serve(e, http.MethodGet, "/page?fields=name", nil)
+	expectStatus(t, w, http.StatusOK)
+	if body := w.Body.String(); !strings.Contains(body, `"items":[{"name":"a"},{"name":""}]`) {
+		t.Fatalf("body = %s", body)
+	}
+}
+
+func TestQueryValidation(t *testing.T) {
+	e := queryEngine(nil)
+	for _, target := range []string{
+		"/items?fields=missing",
+		"/items?sort=status",
+		"/items?filter[id]=1",
+		"/items?filter[status]=pending",
+	} {
+		expectStatus(t, serve(e, http.MethodGet, target, nil), http.StatusBadRequest)
+	}
+	for _, target := range []string{"/items?sort=-id", "/items?filter[status]=open"} {
+		expectStatus(t, serve(e, http.MethodGet, target, nil), http.StatusOK)
+	}
+}
@@ -11,8 +11,6 @@ import (
 	"runtime"
 	"strings"
 	"time"
-
-	"github.com/bytedance/sonic"
 )
 
 // Stack 存储堆栈的关键信息
@@ -35,6 +33,8 @@ type RecoverInfo struct {
 	Traceid     string   `json:"traceid"`
 	Error       string   `json:"error"`
 	Sources     []*Stack `json:"stack"`
+
+	json *jsonCodec
 }
 
 func (r *RecoverInfo) String() string {
@@ -64,13 +64,15 @@ func (r *RecoverInfo) String() string {
 	return sb.String()
 }
 
+// JSON 使用引擎的 JSON 配置编码 (未关联引擎时使用 DefaultJSONConfig)
 func (r *RecoverInfo) JSON() string {
-	var sb strings.Builder
-	enc := sonic.ConfigFastest.NewEncoder(&sb)
-	enc.SetEscapeHTML(false) // 禁止 HTML 转义
-	// enc.SetIndent("", "    ")
-	_ = enc.Encode(r)
-	return sb.String()
+	json := r.json
+	if json == nil {
+		json = newJSONCodec(DefaultJSONConfig())
+	}
+
+	b, _ := json.marshalLog(r)
+	return string(b) + "\n"
 }
 
 // Recovery 是一个增强版的错误恢复中间件，它能打印出发生 panic 的具体源代码片段。
@@ -114,6 +116,7 @@ var Recovery = He(func(c *Ctx) error {
 				Traceid:     c.traceid,
 				Error:       err.Error(),
 				Sources:     stack(3),
+				json:        c.engine.json,
 			}
 
 			// --- 构建漂亮的日志 ---
@@ -122,8 +125,14 @@ var Recovery = He(func(c *Ctx) error {
 			} else {
 				fmt.Print(info.String())
 			}
+			c.engine.reportPanic(c, err, info.Sources)
 
-			_ = c.Send(ErrInternalServerError()) // 返回 500 响应
+			// 响应已写出 (如流式响应或 WebSocket 已接管连接) 时无法再发送错误
+			if !c.Writer.Written() {
+				_ = c.Send(ErrInternalServerError()) // 返回 500 响应
+			} else {
+				gc.Abort()
+			}
 		}
 	}()
 
@@ -9,7 +9,6 @@ import (
 	"sync"
 
 	"github.com/baagod/sgin/v2/helper"
-	"github.com/bytedance/sonic"
 )
 
 var (
@@ -30,13 +29,15 @@ func mainMod() string {
 func DefaultSchemaNamer(t reflect.Type, hint string) string {
 	t = helper.Deref(t)
 
-	name := t.Name()
+	name := typeName(t)
 	if name == "" {
 		name = hint
+	} else if t == resultType {
+		name = "Result" // Result 是 ResultOf[any] 的别名，保持原有的组件名称。
 	}
 
 	pkgPath := t.PkgPath()
-	if pkgPath == "main" {
+	if pkgPath == "main" || pkgPath == sginPkgPath { // sgin 自身的类型 (如 Result) 不加前缀
 		pkgPath = ""
 	} else {
 		pkgPath = strings.TrimPrefix(strings.TrimPrefix(pkgPath, mainMod()), "/")
@@ -58,12 +59,97 @@ func DefaultSchemaNamer(t reflect.Type, hint string) string {
 	return name
 }
 
+var (
+	sginPkgPath = reflect.TypeFor[Registry]().PkgPath()
+	resultType  = reflect.TypeFor[Result]()
+)
+
+// typeName 返回类型的可读名称，泛型类型的参数去掉包路径后拼接在名称之后：
+// ResultOf[pkg.User] -> ResultOfUser，Page[[]pkg.User] -> PageUserList，Page[map[string]int] -> PageMapStringInt。
+func typeName(t reflect.Type) string {
+	name := t.Name()
+	if i := strings.IndexByte(name, '['); i >= 0 && t.PkgPath() != "" {
+		return name[:i] + typeArgsName(name[i+1:len(name)-1])
+	}
+	return name
+}
+
+// typeArgsName 将以逗号分隔的类型参数转换为名称
+func typeArgsName(args string) string {
+	var sb strings.Builder
+	depth, start := 0, 0
+	for i := 0; i <= len(args); i++ {
+		if i < len(args) {
+			switch args[i] {
+			case '[':
+				depth++
+				continue
+			case ']':
+				depth--
+				continue
+			case ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		sb.WriteString(typeExprName(strings.TrimSpace(args[start:i])))
+		start = i + 1
+	}
+	return sb.String()
+}
+
+// typeExprName 将 reflect 输出的类型表达式 (如 *pkg.User、[]int) 转换为名称
+func typeExprName(expr string) string {
+	switch {
+	case expr == "":
+		return ""
+	case strings.HasPrefix(expr, "*"):
+		return typeExprName(expr[1:])
+	case strings.HasPrefix(expr, "map["):
+		depth := 0
+		for i := 3; i < len(expr); i++ {
+			if expr[i] == '[' {
+				depth++
+			} else if expr[i] == ']' {
+				if depth--; depth == 0 {
+					return "Map" + typeExprName(expr[4:i]) + typeExprName(expr[i+1:])
+				}
+			}
+		}
+	case strings.HasPrefix(expr, "["): // 切片或数组
+		if i := strings.IndexByte(expr, ']'); i >= 0 {
+			return typeExprName(expr[i+1:]) + "List"
+		}
+	case strings.HasPrefix(expr, "interface {"):
+		return "Any"
+	}
+
+	base, args := expr, ""
+	if i := strings.IndexByte(expr, '['); i >= 0 {
+		base, args = expr[:i], expr[i+1:len(expr)-1]
+	}
+	if i := strings.LastIndexByte(base, '/'); i >= 0 {
+		base = base[i+1:]
+	}
+	if i := strings.IndexByte(base, '.'); i >= 0 {
+		base = base[i+1:]
+	}
+
+	return helper.UpperFirst(base) + typeArgsName(args)
+}
+
 type Registry struct {
 	Namer      func(reflect.Type, string) string `yaml:"-"`
 	Prefix     string
 	schemas    map[string]*Schema
 	registered map[reflect.Type]bool
 	types      map[string]reflect.Type
+	overrides  map[reflect.Type]*Schema
+	derived    map[string]any // 派生组件 (如统一响应包装后的结构) 的名称及其来源
+	json       *jsonCodec     // 引擎的 JSON 编解码器，用于解析标签中的 JSON 值。
 }
 
 func NewRegistry(prefix string, namer func(reflect.Type, string) string) *Registry {
@@ -73,15 +159,60 @@ func NewRegistry(prefix string, namer func(reflect.Type, string) string) *Regist
 		schemas:    map[string]*Schema{},
 		registered: map[reflect.Type]bool{},
 		types:      map[string]reflect.Type{},
+		overrides:  map[reflect.Type]*Schema{},
+		derived:    map[string]any{},
 	}
 }
 
+// Override 使用 s 作为类型 t 的 Schema，优先于 SchemaProvider 和反射生成的 Schema。
+// 常用于无法添加方法的第三方类型，如 decimal.Decimal、uuid.UUID。
+func (r *Registry) Override(t reflect.Type, s *Schema) {
+	if r.overrides == nil {
+		r.overrides = map[reflect.Type]*Schema{}
+	}
+	r.overrides[t] = s
+}
+
 func (r *Registry) Schema(t reflect.Type, hint ...string) *Schema {
+	if s, ok := r.overrides[t]; ok {
+		return s.Clone() // 字段标签会修改返回的 Schema
+	}
+
 	nullable := t.Kind() == reflect.Ptr
 	if nullable {
 		t = t.Elem()
 	}
 
+	if s, ok := r.overrides[t]; ok {
+		clone := s.Clone()
+		clone.Nullable = clone.Nullable || nullable
+		return clone
+	}
+	if p, ok := reflect.New(t).Interface().(SchemaProvider); ok {
+		if ps := p.OpenAPISchema(r); ps != nil { // 返回 nil 时使用反射生成的 Schema
+			clone := ps.Clone() // 提供者可能返回共享的 Schema
+			clone.Nullable = clone.Nullable || nullable
+			return clone
+		}
+	}
+
+	s := r.reflectSchema(t, nullable, hint...)
+	if s != nil && s.Ref == "" { // 结构体组件在 Struct 中转换
+		s = r.transform(t, s)
+	}
+	return s
+}
+
+// transform 调用类型 t 实现的 SchemaTransformer
+func (r *Registry) transform(t reflect.Type, s *Schema) *Schema {
+	if tr, ok := reflect.New(t).Interface().(SchemaTransformer); ok {
+		return tr.TransformSchema(r, s)
+	}
+	return s
+}
+
+// reflectSchema 通过反射生成类型 t (已解引用) 的 Schema
+func (r *Registry) reflectSchema(t reflect.Type, nullable bool, hint ...string) *Schema {
 	switch t {
 	case timeType:
 		return &Schema{Type: TypeString, Format: "date-time", Nullable: nullable}
@@ -93,7 +224,7 @@ func (r *Registry) Schema(t reflect.Type, hint ...string) *Schema {
 		return &Schema{Type: TypeString, Format: "binary"}
 	}
 
-	prefix := t.Name()
+	prefix := typeName(t)
 	if prefix == "" && len(hint) > 0 {
 		prefix = hint[0]
 	}
@@ -139,16 +270,36 @@ func (r *Registry) Schema(t reflect.Type, hint ...string) *Schema {
 }
 
 func (r *Registry) Field(f reflect.StructField, hint string) (s *Schema) {
+	if f.Tag.Get("hidden") == "true" { // 字段仍然参与绑定和序列化，只是不出现在文档中。
+		return nil
+	}
 	if s = r.Schema(f.Type, hint); s == nil {
 		return
 	}
 
-	s.Description = f.Tag.Get("doc")
-	s.ContentEncoding = f.Tag.Get("encoding")
+	// 只覆盖标签中指定的注解，保留 SchemaProvider 和 SchemaTransformer 设置的值。
+	if title := f.Tag.Get("title"); title != "" {
+		s.Title = title
+	}
+	if doc := f.Tag.Get("doc"); doc != "" {
+		s.Description = doc
+	}
+	if encoding := f.Tag.Get("encoding"); encoding != "" {
+		s.ContentEncoding = encoding
+	}
+	s.ReadOnly = s.ReadOnly || f.Tag.Get("readOnly") == "true"
+	s.WriteOnly = s.WriteOnly || f.Tag.Get("writeOnly") == "true"
+	s.Deprecated = s.Deprecated || f.Tag.Get("deprecated") == "true"
 
 	if d, ok := f.Tag.Lookup("default"); ok {
 		s.Default = helper.Convert(f.Type, f.Name, r.DecodeJSON(d, f.Name, s))
 	}
+	if e, ok := f.Tag.Lookup("example"); ok { // 示例只用于文档，保留 JSON 解码后的值 (如 time.Time 的字符串)。
+		s.Examples = []any{r.decodeExample(e, f.Name, s)}
+	}
+	if f.Tag.Get("nullable") == "true" {
+		s.Nullable = true
+	}
 
 	if format := f.Tag.Get("format"); format != "" {
 		switch format {
@@ -181,6 +332,10 @@ func (r *Registry) Field(f reflect.StructField, hint string) (s *Schema) {
 		}
 	}
 
+	if tag := f.Tag.Get("binding"); tag != "" {
+		r.applyBinding(s, f.Type, strings.Split(tag, ","), f.Name)
+	}
+
 	// 借鉴 Huma 逻辑：如果指针带了 omitempty，且没有显式要求 nullable，
 	// 则在文档中将其标记为非 nullable，因为只有 “存在” 和 “缺失” 两种状态。
 	if f.Type.Kind() == reflect.Ptr &&
@@ -192,6 +347,33 @@ func (r *Registry) Field(f reflect.StructField, hint string) (s *Schema) {
 	return s
 }
 
+// derive 以 name 注册由 build 生成的派生组件，key 是可比较的值，唯一标识组件的来源。
+// 相同来源重复注册时返回已有组件，名称已被其他类型或来源使用时 panic。
+func (r *Registry) derive(name string, key any, build func() *Schema) *Schema {
+	if _, ok := r.schemas[name]; ok {
+		if k, exist := r.derived[name]; !exist || k != key {
+			panic(fmt.Errorf("duplicate name: %s, new: %v, existing: %s", name, key, r.origin(name)))
+		}
+		return &Schema{Ref: r.Prefix + name}
+	}
+
+	if r.derived == nil {
+		r.derived = map[string]any{}
+	}
+	r.schemas[name] = &Schema{} // 先占用名称，以便 build 为递归引用返回 $ref。
+	r.derived[name] = key
+	r.schemas[name] = build()
+	return &Schema{Ref: r.Prefix + name}
+}
+
+// origin 描述组件 name 的来源，用于名称冲突的错误信息。
+func (r *Registry) origin(name string) string {
+	if t := r.types[name]; t != nil {
+		return t.String()
+	}
+	return fmt.Sprint(r.derived[name])
+}
+
 func (r *Registry) Struct(t reflect.Type, hint ...string) *Schema {
 	if len(hint) == 0 {
 		// 如果是匿名结构体，会统一使用 Struct 注册，存在命名冲突，可能需要解决。
@@ -201,7 +383,7 @@ func (r *Registry) Struct(t reflect.Type, hint ...string) *Schema {
 	name := r.Namer(t, hint[0])
 	if _, ok := r.schemas[name]; ok {
 		if _, exist := r.registered[t]; !exist {
-			panic(fmt.Errorf("duplicate name: %s, new type: %s, existing type: %s", name, t, r.types[name]))
+			panic(fmt.Errorf("duplicate name: %s, new type: %s, existing: %s", name, t, r.origin(name)))
 		}
 		return &Schema{Ref: r.Prefix + name}
 	}
@@ -233,12 +415,12 @@ func (r *Registry) Struct(t reflect.Type, hint ...string) *Schema {
 			continue
 		}
 
-		fs := r.Field(f, t.Name()+f.Name) // 递归构建 Schema
+		fs := r.Field(f, typeName(t)+f.Name) // 递归构建 Schema
 		if fs == nil {
 			continue
 		}
 
-		if strings.Contains(f.Tag.Get("binding"), "required") {
+		if bindingRequired(f.Tag.Get("binding")) {
 			required = append(required, field) // 添加必须字段
 		}
 
@@ -247,6 +429,7 @@ func (r *Registry) Struct(t reflect.Type, hint ...string) *Schema {
 
 	s.Properties = props
 	s.Required = required
+	r.schemas[name] = r.transform(t, s)
 	return &Schema{Ref: r.Prefix + name}
 }
 
@@ -261,6 +444,14 @@ func (r *Registry) Ref(ref string) *Schema {
 	return r.schemas[ref[len(r.Prefix):]]
 }
 
+// decodeExample 解析 example 标签的值，引用组件的字段 (如结构体) 直接解码原始 JSON。
+func (r *Registry) decodeExample(value, field string, s *Schema) any {
+	if s.Ref != "" {
+		s = &Schema{Type: TypeObject}
+	}
+	return r.DecodeJSON(value, field, s)
+}
+
 // DecodeJSON 根据字段的 Schema 类型，将从 tag 读取的字符串值解析为正确的 Go 类型。
 func (r *Registry) DecodeJSON(value, field string, s *Schema) any {
 	if s.Ref != "" {
@@ -282,9 +473,14 @@ func (r *Registry) DecodeJSON(value, field string, s *Schema) any {
 	}
 
 	// 对于所有其他类型，尝试使用 JSON 解码器进行解析。
+	json := r.json
+	if json == nil { // 未关联引擎
+		json = newJSONCodec(DefaultJSONConfig())
+	}
+
 	var result any
 	value = strings.TrimSpace(value)
-	if err := sonic.ConfigFastest.Unmarshal([]byte(value), &result); err != nil {
+	if err := json.decode([]byte(value), &result, false); err != nil {
 		panic(fmt.Errorf("invalid %s tag value '%s' for field '%s': %w", s.Type, value, field, err))
 	}
 
@@ -0,0 +1,167 @@
+package sgin
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type tagAddress struct {
+	City string `json:"city"`
+}
+
+type tagUser struct {
+	ID      int        `json:"id" title:"ID" readOnly:"true" example:"42"`
+	Secret  string     `json:"secret" writeOnly:"true"`
+	Old     string     `json:"old" deprecated:"true"`
+	Hidden  string     `json:"hidden" hidden:"true"`
+	Nick    *string    `json:"nick" nullable:"true"`
+	Tags    []string   `json:"tags" example:"a,b"`
+	Address tagAddress `json:"address" example:"{\"city\":\"Paris\"}"`
+	Created time.Time  `json:"created" example:"2024-01-02T03:04:05Z"`
+}
+
+func structSchema(t *testing.T, r *Registry, typ reflect.Type) *Schema {
+	t.Helper()
+	ref := r.Schema(typ).Ref
+	s := r.schemas[ref[len(r.Prefix):]]
+	if s == nil {
+		t.Fatalf("component %s not registered", ref)
+	}
+	return s
+}
+
+func TestFieldTags(t *testing.T) {
+	r := NewAPI().Components.Schemas
+	props := structSchema(t, r, reflect.TypeFor[tagUser]()).Properties
+
+	if id := props["id"]; id.Title != "ID" || !id.ReadOnly || !reflect.DeepEqual(id.Examples, []any{float64(42)}) {
+		t.Fatalf("id = %+v", id)
+	}
+	if !props["secret"].WriteOnly || !props["old"].Deprecated || !props["nick"].Nullable {
+		t.Fatal("writeOnly, deprecated or nullable tag ignored")
+	}
+	if _, ok := props["hidden"]; ok {
+		t.Fatal("hidden field documented")
+	}
+	if got := props["tags"].Examples; !reflect.DeepEqual(got, []any{[]string{"a", "b"}}) {
+		t.Fatalf("tags example = %#v", got)
+	}
+	if got := props["created"].Examples; !reflect.DeepEqual(got, []any{"2024-01-02T03:04:05Z"}) {
+		t.Fatalf("created example = %#v", got)
+	}
+
+	// 结构体字段的示例是原始 JSON，而不是引用的组件 Schema。
+	if got := props["address"].Examples; !reflect.DeepEqual(got, []any{map[string]any{"city": "Paris"}}) {
+		t.Fatalf("address example = %#v", got)
+	}
+}
+
+type codes []string
+
+type money struct{ cents int64 }
+
+var moneySchema = &Schema{Type: TypeString, Format: "decimal", Examples: []any{"1.00"}}
+
+func (money) OpenAPISchema(*Registry) *Schema { return moneySchema }
+
+type overrideUser struct {
+	Primary   codes `json:"primary" binding:"dive,min=2"`
+	Secondary codes `json:"secondary"`
+	Price     money `json:"price" example:"\"2.50\"" title:"Price"`
+	Cost      money `json:"cost"`
+}
+
+func TestSchemaOverrideIsCopied(t *testing.T) {
+	override := &Schema{Type: TypeArray, Items: &Schema{Type: TypeString}}
+	r := NewAPI().Components.Schemas
+	r.Override(reflect.TypeFor[codes](), override)
+
+	props := structSchema(t, r, reflect.TypeFor[overrideUser]()).Properties
+	if n := props["primary"].Items.MinLength; n == nil || *n != 2 {
+		t.Fatalf("dive rule not applied: %+v", props["primary"].Items)
+	}
+	if props["secondary"].Items.MinLength != nil || override.Items.MinLength != nil {
+		t.Fatal("dive rule leaked into the shared override")
+	}
+
+	if props["price"].Title != "Price" || props["cost"].Title != "" {
+		t.Fatal("field tag leaked into another field")
+	}
+	if got := props["cost"].Examples; !reflect.DeepEqual(got, []any{"1.00"}) {
+		t.Fatalf("cost example = %#v", got)
+	}
+	if moneySchema.Title != "" || !reflect.DeepEqual(moneySchema.Examples, []any{"1.00"}) {
+		t.Fatalf("provider schema was mutated: %+v", moneySchema)
+	}
+}
+
+func TestSchemaClone(t *testing.T) {
+	s := &Schema{
+		Type:                 TypeObject,
+		Properties:           map[string]*Schema{"a": {Type: TypeArray, Items: &Schema{Type: TypeString}}},
+		AdditionalProperties: &Schema{Type: TypeInteger},
+		Required:             []string{"a"},
+	}
+	c := s.Clone()
+	c.Properties["a"].Items.Format = "uuid"
+	c.AdditionalProperties.(*Schema).Format = "int32"
+	c.Required[0] = "b"
+
+	if s.Properties["a"].Items.Format != "" || s.AdditionalProperties.(*Schema).Format != "" || s.Required[0] != "a" {
+		t.Fatalf("clone shares state with the original: %+v", s)
+	}
+}
+
+type constrainedUser struct {
+	Age   int               `json:"age" binding:"required,gte=18,lt=130"`
+	Name  string            `json:"name" binding:"min=2,max=20,alpha"`
+	Code  string            `json:"code" binding:"len=6"`
+	Role  string            `json:"role" binding:"oneof=admin 'super user'"`
+	Level int               `json:"level" binding:"omitempty,oneof=1 2 3"`
+	Email string            `json:"email" binding:"required_if=Role admin,email"`
+	Tags  []string          `json:"tags" binding:"gt=0,unique,dive,max=8"`
+	Meta  map[string]string `json:"meta" binding:"dive,startswith=x"`
+	Wait  time.Duration     `json:"wait" binding:"min=1s"`
+}
+
+func TestBindingConstraints(t *testing.T) {
+	r := NewAPI().Components.Schemas
+	s := structSchema(t, r, reflect.TypeFor[constrainedUser]())
+	props := s.Properties
+	num := func(n float64) *float64 { return &n }
+	length := func(n int) *int { return &n }
+
+	if !reflect.DeepEqual(s.Required, []string{"age"}) {
+		t.Fatalf("required = %v", s.Required)
+	}
+	if age := props["age"]; !reflect.DeepEqual(age.Minimum, num(18)) || !reflect.DeepEqual(age.ExclusiveMaximum, num(130)) {
+		t.Fatalf("age = %+v", age)
+	}
+	if name := props["name"]; !reflect.DeepEqual(name.MinLength, length(2)) || !reflect.DeepEqual(name.MaxLength, length(20)) || name.Pattern != "^[a-zA-Z]+$" {
+		t.Fatalf("name = %+v", name)
+	}
+	if code := props["code"]; !reflect.DeepEqual(code.MinLength, length(6)) || !reflect.DeepEqual(code.MaxLength, length(6)) {
+		t.Fatalf("code = %+v", code)
+	}
+	if got := props["role"].Enum; !reflect.DeepEqual(got, []any{"admin", "super user"}) {
+		t.Fatalf("role enum = %#v", got)
+	}
+	if got := props["level"].Enum; !reflect.DeepEqual(got, []any{float64(1), float64(2), float64(3)}) {
+		t.Fatalf("level enum = %#v", got)
+	}
+	if props["email"].Format != "email" {
+		t.Fatalf("email = %+v", props["email"])
+	}
+
+	tags := props["tags"]
+	if !reflect.DeepEqual(tags.MinItems, length(1)) || !tags.UniqueItems || !reflect.DeepEqual(tags.Items.MaxLength, length(8)) || tags.MaxLength != nil {
+		t.Fatalf("tags = %+v, items = %+v", tags, tags.Items)
+	}
+	if elem, _ := props["meta"].AdditionalProperties.(*Schema); elem == nil || elem.Pattern != "^x" {
+		t.Fatalf("meta = %+v", props["meta"])
+	}
+	if wait := props["wait"]; wait.Minimum != nil {
+		t.Fatalf("duration bound = %v", *wait.Minimum)
+	}
+}
@@ -0,0 +1,262 @@
+package sgin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const reportedKey = "_baa/sgin/reported"
+
+// redacted 脱敏后的占位值
+const redacted = "[REDACTED]"
+
+// ErrorReporter 接收服务端错误 (panic 和 ErrorHandler 产生的 5xx)，用于发送到错误跟踪系统。
+// Report 在请求协程中同步调用，耗时的实现应自行异步处理。
+type ErrorReporter interface {
+	Report(r *ErrorReport)
+}
+
+// ErrorReport 描述一次服务端错误
+type ErrorReport struct {
+	Time        time.Time       `json:"time"`
+	Fingerprint string          `json:"fingerprint"`          // 用于去重和聚合的指纹
+	Error       string          `json:"error"`                // 错误消息
+	Panic       bool            `json:"panic,omitempty"`      // 是否由 panic 产生
+	Status      int             `json:"status"`               // 响应状态码
+	Route       string          `json:"route"`                // 路由模板，如 /users/:id。
+	TraceID     string          `json:"traceid"`              // 请求的跟踪 ID
+	User        string          `json:"user,omitempty"`       // 用户标识，来自 ReportConfig.User。
+	Suppressed  int             `json:"suppressed,omitempty"` // 自上次上报以来被去重或限流丢弃的相同错误数量
+	Request     RequestSnapshot `json:"request"`
+	Stack       []*Stack        `json:"stack,omitempty"` // panic 的调用栈
+
+	Err error `json:"-"` // 原始错误
+}
+
+// RequestSnapshot 是脱敏后的请求快照 (不含请求体)
+type RequestSnapshot struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	IP      string            `json:"ip"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// ReportConfig 定义错误上报的配置
+type ReportConfig struct {
+	Reporter  ErrorReporter       // 上报目标，如 NewFileReporter("errors.jsonl")。
+	User      func(c *Ctx) string // 返回当前请求的用户标识
+	Redact    []string            // 需要脱敏的请求头和查询参数名称 (不区分大小写)
+	Dedupe    time.Duration       // 相同指纹的错误在该时间窗口内只上报一次，默认 1 分钟，<= 0 不去重。
+	RateLimit int                 // 每分钟最多上报的错误数量，默认 60，<= 0 不限制。
+}
+
+// DefaultReportConfig 返回默认的错误上报配置
+func DefaultReportConfig() ReportConfig {
+	return ReportConfig{
+		Redact: []string{
+			HeaderAuthorization, HeaderProxyAuthorization, HeaderCookie, HeaderSetCookie, "X-Api-Key", "Api-Key",
+			"password", "passwd", "secret", "token", "access_token", "refresh_token",
+		},
+		Dedupe:    time.Minute,
+		RateLimit: 60,
+	}
+}
+
+// reporter 在 ErrorReporter 之上实现去重和限流
+type reporter struct {
+	cfg ReportConfig
+
+	mu          sync.Mutex
+	seen        map[string]time.Time // 指纹 -> 上次上报时间
+	suppressed  map[string]int       // 指纹 -> 被丢弃的次数
+	windowStart time.Time
+	windowCount int
+}
+
+func newReporter(cfg ReportConfig) *reporter {
+	return &reporter{cfg: cfg, seen: map[string]time.Time{}, suppressed: map[string]int{}}
+}
+
+// allow 检查指纹为 fp 的错误是否应当上报，并返回此前被丢弃的数量。
+func (r *reporter) allow(fp string, now time.Time) (bool, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cfg.Dedupe > 0 {
+		if last, ok := r.seen[fp]; ok && now.Sub(last) < r.cfg.Dedupe {
+			r.suppressed[fp]++
+			return false, 0
+		}
+	}
+
+	if r.cfg.RateLimit > 0 {
+		if now.Sub(r.windowStart) >= time.Minute {
+			r.windowStart, r.windowCount = now, 0
+		}
+		if r.windowCount >= r.cfg.RateLimit {
+			r.suppressed[fp]++
+			return false, 0
+		}
+		r.windowCount++
+	}
+
+	if r.cfg.Dedupe > 0 {
+		if len(r.seen) >= 10000 { // 防止指纹无限增长
+			for k, t := range r.seen {
+				if now.Sub(t) >= r.cfg.Dedupe {
+					delete(r.seen, k)
+				}
+			}
+		}
+		r.seen[fp] = now
+	}
+
+	n := r.suppressed[fp]
+	delete(r.suppressed, fp)
+	return true, n
+}
+
+// report 构建并上报错误，stack 不为 nil 时表示 panic。
+func (r *reporter) report(c *Ctx, err error, status int, stack []*Stack) {
+	route := c.Path(true)
+	if route == "" {
+		route = c.Path()
+	}
+
+	// 指纹由路由、状态码、错误类型和 panic 位置组成，错误消息中的可变部分 (如 ID) 不参与计算。
+	parts := []string{c.Method(), route, fmt.Sprint(status), fmt.Sprintf("%T", innermost(err))}
+	if len(stack) > 0 {
+		parts = append(parts, fmt.Sprintf("%s:%d", stack[0].File, stack[0].Line))
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	fp := hex.EncodeToString(sum[:8])
+
+	now := time.Now()
+	ok, suppressed := r.allow(fp, now)
+	if !ok {
+		return
+	}
+
+	report := &ErrorReport{
+		Time:        now,
+		Fingerprint: fp,
+		Error:       err.Error(),
+		Panic:       stack != nil,
+		Status:      status,
+		Route:       route,
+		TraceID:     c.TraceID(),
+		Suppressed:  suppressed,
+		Request:     r.snapshot(c),
+		Stack:       stack,
+		Err:         err,
+	}
+	if r.cfg.User != nil {
+		report.User = r.cfg.User(c)
+	}
+
+	r.cfg.Reporter.Report(report)
+}
+
+// snapshot 生成脱敏后的请求快照
+func (r *reporter) snapshot(c *Ctx) RequestSnapshot {
+	sensitive := func(name string) bool {
+		return slices.ContainsFunc(r.cfg.Redact, func(s string) bool { return strings.EqualFold(s, name) })
+	}
+
+	u := *c.Request.URL
+	if query := u.Query(); len(query) > 0 {
+		for key := range query {
+			if sensitive(key) {
+				query[key] = []string{redacted}
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+
+	headers := make(map[string]string, len(c.Request.Header))
+	for key, values := range c.Request.Header {
+		if sensitive(key) {
+			headers[key] = redacted
+		} else {
+			headers[key] = strings.Join(values, ", ")
+		}
+	}
+
+	return RequestSnapshot{
+		Method:  c.Request.Method,
+		URL:     (&url.URL{Path: u.Path, RawQuery: u.RawQuery}).String(),
+		IP:      c.IP(),
+		Headers: headers,
+	}
+}
+
+// innermost 返回错误链中最内层的错误
+func innermost(err error) error {
+	for {
+		u, ok := err.(interface{ Unwrap() error })
+		if !ok || u.Unwrap() == nil {
+			return err
+		}
+		err = u.Unwrap()
+	}
+}
+
+// reportPanic 上报 panic，之后由 Recovery 产生的 500 响应不会重复上报。
+func (e *Engine) reportPanic(c *Ctx, err error, stack []*Stack) {
+	if e.reporter == nil {
+		return
+	}
+	c.Get(reportedKey, true)
+	e.reporter.report(c, err, http.StatusInternalServerError, stack)
+}
+
+// reportError 上报 ErrorHandler 处理的 5xx 错误
+func (e *Engine) reportError(c *Ctx, err error) {
+	if e.reporter == nil || c.Get(reportedKey) != nil {
+		return
+	}
+	if status := errorStatus(c, err); status >= 500 {
+		c.Get(reportedKey, true)
+		e.reporter.report(c, err, status, nil)
+	}
+}
+
+// FileReporter 将错误报告以 JSON Lines 格式追加写入本地文件
+type FileReporter struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// NewFileReporter 打开 (不存在时创建) name 文件用于追加写入错误报告
+func NewFileReporter(name string) (*FileReporter, error) {
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileReporter{f: f}, nil
+}
+
+func (r *FileReporter) Report(report *ErrorReport) {
+	b, err := json.Marshal(report)
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, _ = r.f.Write(append(b, '\n'))
+}
+
+// Close 关闭文件
+func (r *FileReporter) Close() error {
+	return r.f.Close()
+}
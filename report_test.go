@@ -0,0 +1,101 @@
+package sgin
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memReporter 将错误报告保存在内存中
+type memReporter struct {
+	mu      sync.Mutex
+	reports []*ErrorReport
+}
+
+func (r *memReporter) Report(report *ErrorReport) {
+	r.mu.Lock()
+	r.reports = append(r.reports, report)
+	r.mu.Unlock()
+}
+
+func TestReporterDedupe(t *testing.T) {
+	r := newReporter(ReportConfig{Dedupe: time.Minute})
+	now := time.Now()
+
+	if ok, _ := r.allow("a", now); !ok {
+		t.Fatal("first error was dropped")
+	}
+	for i := 1; i <= 3; i++ {
+		if ok, _ := r.allow("a", now.Add(time.Duration(i)*time.Second)); ok {
+			t.Fatal("duplicate error was reported")
+		}
+	}
+	if ok, _ := r.allow("b", now); !ok {
+		t.Fatal("different fingerprint was dropped")
+	}
+
+	ok, suppressed := r.allow("a", now.Add(time.Minute))
+	if !ok || suppressed != 3 {
+		t.Fatalf("after window: ok=%v suppressed=%d", ok, suppressed)
+	}
+}
+
+func TestReporterRateLimit(t *testing.T) {
+	r := newReporter(ReportConfig{RateLimit: 2})
+	now := time.Now()
+
+	for i, want := range []bool{true, true, false, false} {
+		if ok, _ := r.allow("a", now); ok != want {
+			t.Fatalf("report %d: ok=%v, want %v", i, ok, want)
+		}
+	}
+
+	ok, suppressed := r.allow("a", now.Add(time.Minute))
+	if !ok || suppressed != 2 {
+		t.Fatalf("next window: ok=%v suppressed=%d", ok, suppressed)
+	}
+}
+
+func TestReportErrors(t *testing.T) {
+	mem := &memReporter{}
+	e := testEngine(Config{Report: func(c *ReportConfig) {
+		c.Reporter = mem
+		c.User = func(*Ctx) string { return "u1" }
+	}})
+	e.GET("/fail/:id", He(func(c *Ctx) error {
+		return errors.New("db down for " + c.Param("page"))
+	}))
+	e.GET("/missing", He(func(c *Ctx) error {
+		return ErrNotFound()
+	}))
+	e.GET("/panic", He(func(c *Ctx) error {
+		panic("boom")
+	}))
+
+	expectStatus(t, serve(e, http.MethodGet, "/fail/1?token=abc&page=2", nil, HeaderAuthorization, "Bearer x"), http.StatusInternalServerError)
+	expectStatus(t, serve(e, http.MethodGet, "/fail/2?page=3", nil), http.StatusInternalServerError) // 相同指纹，被去重。
+	expectStatus(t, serve(e, http.MethodGet, "/missing", nil), http.StatusNotFound)                  // 4xx 不上报
+	expectStatus(t, serve(e, http.MethodGet, "/panic", nil), http.StatusInternalServerError)
+
+	if len(mem.reports) != 2 {
+		t.Fatalf("reports = %d, want 2", len(mem.reports))
+	}
+
+	r := mem.reports[0]
+	if r.Route != "/fail/:id" || r.Status != 500 || r.User != "u1" || r.Panic || r.Error != "db down for 2" {
+		t.Fatalf("report = %+v", r)
+	}
+	if r.Request.Headers[HeaderAuthorization] != redacted {
+		t.Fatalf("headers = %v", r.Request.Headers)
+	}
+	if u := r.Request.URL; strings.Contains(u, "abc") || !strings.Contains(u, "REDACTED") || !strings.Contains(u, "page=2") {
+		t.Fatalf("url = %s", u)
+	}
+
+	if p := mem.reports[1]; !p.Panic || len(p.Stack) == 0 || p.Route != "/panic" {
+		t.Fatalf("panic report = %+v", p)
+	}
+}
@@ -6,39 +6,45 @@ import (
 	"github.com/spf13/cast"
 )
 
-type Result struct {
+// Result 是标准化的业务响应结构，Data 可以是任意类型。
+// 需要在 OpenAPI 文档中描述 Data 的结构时使用 ResultOf。
+type Result = ResultOf[any]
+
+// ResultOf 是 Data 类型为 T 的 Result，OpenAPI 文档会生成完整的 data 结构，
+// 组件以 T 命名，例如 ResultOf[User] 的组件名称为 ResultOfUser。
+type ResultOf[T any] struct {
 	Event  string `json:"event"`  // 事件标识
 	Status int    `json:"status"` // 自定义状态码，经常用于定义请求成功或失败等错误状态 (非 HTTP 状态码)
 	Code   int    `json:"code"`   // 自定义代码，经常与 Status 关联。例如: Status=0 时，Code=N。
 	Count  int    `json:"count"`  // 如果 Data 返回列表，可以在这里设置列表长度。
 	Msg    string `json:"msg"`    // 结果消息
-	Data   any    `json:"data"`   // 结果数据
+	Data   T      `json:"data"`   // 结果数据
 }
 
-func (r *Result) newStatus(status any, code ...any) *Result {
+func (r *ResultOf[T]) newStatus(status any, code ...any) *ResultOf[T] {
 	st := cast.ToInt(status)
 	if len(code) == 0 {
-		return &Result{Status: st}
+		return &ResultOf[T]{Status: st}
 	}
-	return &Result{Status: st, Code: cast.ToInt(code[0])}
+	return &ResultOf[T]{Status: st, Code: cast.ToInt(code[0])}
 }
 
-func (r *Result) newOK(data ...any) *Result {
+func (r *ResultOf[T]) newOK(data ...T) *ResultOf[T] {
 	if len(data) == 0 {
-		return &Result{Status: 1}
+		return &ResultOf[T]{Status: 1}
 	}
-	return &Result{Data: data[0], Status: 1}
+	return &ResultOf[T]{Data: data[0], Status: 1}
 }
 
-func (r *Result) NewFailed(data ...any) *Result {
+func (r *ResultOf[T]) NewFailed(data ...T) *ResultOf[T] {
 	if len(data) == 0 {
-		return &Result{}
+		return &ResultOf[T]{}
 	}
-	return &Result{Data: data[0]}
+	return &ResultOf[T]{Data: data[0]}
 }
 
 // SetStatus 设置 status, code
-func (r *Result) SetStatus(status any, code ...any) (res *Result) {
+func (r *ResultOf[T]) SetStatus(status any, code ...any) (res *ResultOf[T]) {
 	if res = r; res == nil {
 		res = r.newStatus(status, code...)
 	} else if r.Status = cast.ToInt(status); len(code) > 0 {
@@ -48,35 +54,35 @@ func (r *Result) SetStatus(status any, code ...any) (res *Result) {
 }
 
 // SetCode 设置 code
-func (r *Result) SetCode(code any) *Result {
+func (r *ResultOf[T]) SetCode(code any) *ResultOf[T] {
 	if r == nil {
-		return &Result{Code: cast.ToInt(code)}
+		return &ResultOf[T]{Code: cast.ToInt(code)}
 	}
 	r.Code = cast.ToInt(code)
 	return r
 }
 
 // SetEvent 设置事件
-func (r *Result) SetEvent(e string) *Result {
+func (r *ResultOf[T]) SetEvent(e string) *ResultOf[T] {
 	if r == nil {
-		return &Result{Event: e}
+		return &ResultOf[T]{Event: e}
 	}
 	r.Event = e
 	return r
 }
 
 // SetMsg 设置消息
-func (r *Result) SetMsg(format any, a ...any) *Result {
+func (r *ResultOf[T]) SetMsg(format any, a ...any) *ResultOf[T] {
 	m := fmt.Sprintf(fmt.Sprint(format), a...)
 	if r == nil {
-		return &Result{Msg: m}
+		return &ResultOf[T]{Msg: m}
 	}
 	r.Msg = m
 	return r
 }
 
 // OK 设置成功 (status=1) 数据
-func (r *Result) OK(data ...any) (res *Result) {
+func (r *ResultOf[T]) OK(data ...T) (res *ResultOf[T]) {
 	if res = r; res == nil {
 		res = r.newOK(data...)
 	} else if r.Status = 1; len(data) > 0 {
@@ -86,7 +92,7 @@ func (r *Result) OK(data ...any) (res *Result) {
 }
 
 // Failed 设置失败 (status=0) 数据
-func (r *Result) Failed(data ...any) (res *Result) {
+func (r *ResultOf[T]) Failed(data ...T) (res *ResultOf[T]) {
 	if res = r; res == nil {
 		res = r.NewFailed(data...)
 	} else if r.Status = 0; len(data) > 0 {
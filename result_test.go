@@ -0,0 +1,55 @@
+package sgin
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type resultUser struct {
+	ID int `json:"id"`
+}
+
+func TestResultCompat(t *testing.T) {
+	var r *Result
+	if r = r.SetMsg("ok").OK("v1"); r.Status != 1 || r.Msg != "ok" || r.Data != "v1" {
+		t.Fatalf("unexpected result %+v", r)
+	}
+
+	lit := Result{Code: 1001, Data: []int{1}}
+	if _, ok := lit.Data.([]int); !ok {
+		t.Fatal("Result.Data should accept any value")
+	}
+
+	var typed *ResultOf[resultUser]
+	if typed = typed.OK(resultUser{ID: 1}); typed.Data.ID != 1 {
+		t.Fatalf("unexpected result %+v", typed)
+	}
+}
+
+func TestResultSchemaNames(t *testing.T) {
+	a := NewAPI()
+	if ref := a.Schema(reflect.TypeFor[Result]()).Ref; !strings.HasSuffix(ref, "/Result") {
+		t.Fatalf("Result ref = %q", ref)
+	}
+	if ref := a.Schema(reflect.TypeFor[ResultOf[resultUser]]()).Ref; !strings.HasSuffix(ref, "/ResultOfResultUser") {
+		t.Fatalf("ResultOf[resultUser] ref = %q", ref)
+	}
+	if ref := a.Schema(reflect.TypeFor[ResultOf[[]resultUser]]()).Ref; !strings.HasSuffix(ref, "/ResultOfResultUserList") {
+		t.Fatalf("ResultOf[[]resultUser] ref = %q", ref)
+	}
+}
+
+func TestResultHandler(t *testing.T) {
+	e := testEngine()
+	e.GET("/v", Ho(func(c *Ctx, _ struct{}) (r *Result) {
+		return r.OK("v1")
+	}))
+
+	w := serve(e, http.MethodGet, "/v", nil)
+	expectStatus(t, w, http.StatusOK)
+	if !strings.Contains(w.Body.String(), `"data":"v1"`) {
+		t.Fatalf("body = %s", w.Body.String())
+	}
+}
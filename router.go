@@ -1,9 +1,11 @@
 package sgin
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
+	"github.com/baagod/sgin/v2/helper"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,6 +21,7 @@ type IRouter interface {
 	Handle(string, string, Handler, ...AddOperation) IRouter
 	Any(string, Handler, ...AddOperation) IRouter
 	Match([]string, string, Handler, ...AddOperation) IRouter
+	WS(string, Handler, ...AddOperation) IRouter
 	Group(string, ...AddOperation) IRouter
 	Static(string, string) IRouter
 	StaticFile(string, string) IRouter
@@ -79,17 +82,22 @@ func (r *Router) Any(path string, h Handler, ops ...AddOperation) IRouter {
 	return r.Match(anyMethods, path, h, ops...)
 }
 
+// WS 注册一个 WebSocket 端点，h 通常由 WebSocket 创建。
+func (r *Router) WS(path string, h Handler, ops ...AddOperation) IRouter {
+	return r.Handle(http.MethodGet, path, h, ops...)
+}
+
 func (r *Router) Handle(method, path string, h Handler, ops ...AddOperation) IRouter {
+	op := r.op.Clone()
+	for _, f := range ops {
+		f(op)
+	}
 	if r.e.cfg.OpenAPI != nil {
 		if a := hMeta.Pop(h); a != nil {
-			op := r.op.Clone()
-			for _, f := range ops {
-				f(op)
-			}
 			r.api.Register(op, r.fullPath(path), method, a)
 		}
 	}
-	r.i.Handle(method, path, h)
+	r.i.Handle(method, path, r.routeHandlers(op, h)...)
 	return r
 }
 
@@ -101,12 +109,19 @@ func (r *Router) Match(methods []string, path string, h Handler, ops ...AddOpera
 				for _, f := range ops {
 					f(op)
 				}
+				if op.OperationID != "" && len(methods) > 1 { // 每个方法的 operationId 必须唯一，如 x -> getX、postX。
+					op.OperationID = strings.ToLower(method) + helper.UpperFirst(op.OperationID)
+				}
 				r.api.Register(op, r.fullPath(path), method, meta)
 			}
 			hMeta.Delete(h)
 		}
 	}
-	r.i.Match(methods, path, h)
+	op := r.op.Clone()
+	for _, f := range ops {
+		f(op)
+	}
+	r.i.Match(methods, path, r.routeHandlers(op, h)...)
 	return r
 }
 
@@ -115,6 +130,9 @@ func (r *Router) Group(path string, ops ...AddOperation) IRouter {
 	for _, f := range ops {
 		f(op)
 	}
+	if op.OperationID != "" { // operationId 必须唯一，不能由组内的多个路由共享。
+		panic(fmt.Errorf("sgin: OperationID cannot be set on group %s, set it on each route", r.fullPath(path)))
+	}
 	return &Router{
 		i:    r.i.Group(path),
 		e:    r.e,
@@ -124,9 +142,9 @@ func (r *Router) Group(path string, ops ...AddOperation) IRouter {
 	}
 }
 
+// Static 提供 root 目录下的静态文件，若存在 "<文件>.gz" 且客户端接受 gzip，则直接发送预压缩文件。
 func (r *Router) Static(path, root string) IRouter {
-	r.i.Static(path, root)
-	return r
+	return r.StaticFS(path, gin.Dir(root, false))
 }
 
 func (r *Router) StaticFile(name, root string) IRouter {
@@ -139,8 +157,9 @@ func (r *Router) StaticFileFS(name, root string, fs http.FileSystem) IRouter {
 	return r
 }
 
+// StaticFS 与 Static 相同，但使用自定义的 http.FileSystem。
 func (r *Router) StaticFS(name string, root http.FileSystem) IRouter {
-	r.i.StaticFS(name, root)
+	r.i.Group(name, precompressed(root)).StaticFS("/", root)
 	return r
 }
 
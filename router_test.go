@@ -0,0 +1,50 @@
+package sgin
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func noop(*Ctx) error { return nil }
+
+func TestOperationIDGenerated(t *testing.T) {
+	e := testEngine(Config{OpenAPI: NewAPI()})
+	e.GET("/users/:id", He(noop))
+	e.GET("/users/:id/", He(noop)) // 与上一个路由生成相同的标识
+
+	paths := e.Spec().Paths
+	if id := paths["/users/{id}"].Get.OperationID; id != "getUsersById" {
+		t.Fatalf("operationId = %q", id)
+	}
+	if id := paths["/users/{id}/"].Get.OperationID; id != "getUsersById2" {
+		t.Fatalf("operationId = %q", id)
+	}
+}
+
+func TestOperationIDExplicit(t *testing.T) {
+	e := testEngine(Config{OpenAPI: NewAPI()})
+	e.Any("/x", He(noop), OperationID("x"))
+	e.Match([]string{"GET"}, "/y", He(noop), OperationID("y"))
+
+	item := e.Spec().Paths["/x"]
+	if item.Get.OperationID != "getX" || item.Post.OperationID != "postX" || item.Trace.OperationID != "traceX" {
+		t.Fatalf("operationIds = %q, %q, %q", item.Get.OperationID, item.Post.OperationID, item.Trace.OperationID)
+	}
+	if id := e.Spec().Paths["/y"].Get.OperationID; id != "y" {
+		t.Fatalf("single method operationId = %q", id)
+	}
+
+	v := mustPanic(t, func() { e.GET("/z", He(noop), OperationID("y")) })
+	if !strings.Contains(fmt.Sprint(v), `duplicate operationId "y"`) {
+		t.Fatalf("unexpected panic %v", v)
+	}
+}
+
+func TestOperationIDGroup(t *testing.T) {
+	e := testEngine(Config{OpenAPI: NewAPI()})
+	v := mustPanic(t, func() { e.Group("/v1", OperationID("v1")) })
+	if !strings.Contains(fmt.Sprint(v), "cannot be set on group /v1") {
+		t.Fatalf("unexpected panic %v", v)
+	}
+}
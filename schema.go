@@ -8,6 +8,7 @@ import (
 	"net/netip"
 	"net/url"
 	"reflect"
+	"slices"
 	"time"
 
 	"github.com/baagod/sgin/v2/helper"
@@ -33,6 +34,20 @@ var (
 	fileHeaderType = reflect.TypeFor[multipart.FileHeader]()
 )
 
+// SchemaProvider 由需要自定义 Schema 的类型实现，返回的 Schema 完全替代反射生成的 Schema。
+//
+//	func (Money) OpenAPISchema(r *sgin.Registry) *sgin.Schema {
+//	    return &sgin.Schema{Type: sgin.TypeString, Pattern: `^\d+\.\d{2}$`, Examples: []any{"9.99"}}
+//	}
+type SchemaProvider interface {
+	OpenAPISchema(r *Registry) *Schema
+}
+
+// SchemaTransformer 由需要调整生成结果的类型实现，s 为反射生成的 Schema (结构体为组件本身)，返回调整后的 Schema。
+type SchemaTransformer interface {
+	TransformSchema(r *Registry, s *Schema) *Schema
+}
+
 type Schema struct {
 	Type                 any                `yaml:"type,omitempty"`
 	Nullable             bool               `yaml:"-"`
@@ -42,6 +57,20 @@ type Schema struct {
 	Format               string             `yaml:"format,omitempty"`
 	ContentEncoding      string             `yaml:"contentEncoding,omitempty"`
 	Default              any                `yaml:"default,omitempty"`
+	Examples             []any              `yaml:"examples,omitempty"`
+	ReadOnly             bool               `yaml:"readOnly,omitempty"`  // 只出现在响应中
+	WriteOnly            bool               `yaml:"writeOnly,omitempty"` // 只出现在请求中
+	Deprecated           bool               `yaml:"deprecated,omitempty"`
+	Minimum              *float64           `yaml:"minimum,omitempty"`
+	ExclusiveMinimum     *float64           `yaml:"exclusiveMinimum,omitempty"`
+	Maximum              *float64           `yaml:"maximum,omitempty"`
+	ExclusiveMaximum     *float64           `yaml:"exclusiveMaximum,omitempty"`
+	MinLength            *int               `yaml:"minLength,omitempty"`
+	MaxLength            *int               `yaml:"maxLength,omitempty"`
+	Pattern              string             `yaml:"pattern,omitempty"`
+	MinItems             *int               `yaml:"minItems,omitempty"`
+	MaxItems             *int               `yaml:"maxItems,omitempty"`
+	UniqueItems          bool               `yaml:"uniqueItems,omitempty"`
 	Items                *Schema            `yaml:"items,omitempty"`                // For arrays
 	AdditionalProperties any                `yaml:"additionalProperties,omitempty"` // Schema or bool
 	Properties           map[string]*Schema `yaml:"properties,omitempty"`
@@ -57,6 +86,32 @@ func (s *Schema) MarshalYAML() (any, error) {
 	return tmp, nil // 返回指针会有递归错误
 }
 
+// Clone 返回 s 的深拷贝，修改拷贝的 Items、Properties 等不会影响 s。
+func (s *Schema) Clone() *Schema {
+	if s == nil {
+		return nil
+	}
+
+	clone := *s
+	clone.Items = s.Items.Clone()
+	if ap, ok := s.AdditionalProperties.(*Schema); ok {
+		clone.AdditionalProperties = ap.Clone()
+	}
+	if s.Properties != nil {
+		clone.Properties = make(map[string]*Schema, len(s.Properties))
+		for k, v := range s.Properties {
+			clone.Properties[k] = v.Clone()
+		}
+	}
+	if t, ok := s.Type.([]any); ok {
+		clone.Type = slices.Clone(t)
+	}
+	clone.Examples = slices.Clone(s.Examples)
+	clone.Enum = slices.Clone(s.Enum)
+	clone.Required = slices.Clone(s.Required)
+	return &clone
+}
+
 // Field 用于存储字段的详细信息，包括其直接父级类型。
 type Field struct {
 	Parent reflect.Type
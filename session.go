@@ -0,0 +1,360 @@
+package sgin
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const sessionKey = "_baa/sgin/session"
+
+// sessionIDLen 会话 ID 的长度 (32 字节随机数的 base64url 编码)
+const sessionIDLen = 43
+
+// sessionStoreTTL 是未设置任何超时时会话在 Store 中的有效期，每次访问后刷新。
+const sessionStoreTTL = 30 * 24 * time.Hour
+
+// SessionConfig 定义会话中间件的配置
+type SessionConfig struct {
+	Store           Store         // 会话存储，默认 NewMemoryStore()。
+	CookieName      string        // 保存会话 ID 的 Cookie 名称，默认 "sgin_session"。
+	IdleTimeout     time.Duration // 空闲超时，超过该时间未访问的会话失效，默认 30 分钟。
+	AbsoluteTimeout time.Duration // 绝对超时，自创建起超过该时间的会话失效，默认 24 小时。都为 0 时会话在存储中保留 30 天。
+
+	Path     string        // 默认 "/"
+	Domain   string        // 默认为空
+	Secure   bool          // 仅通过 HTTPS 发送
+	HttpOnly bool          // 禁止 JavaScript 访问，默认 true。
+	SameSite http.SameSite // 默认 http.SameSiteLaxMode
+}
+
+// DefaultSessionConfig 返回默认的会话配置
+func DefaultSessionConfig() SessionConfig {
+	return SessionConfig{
+		CookieName:      "sgin_session",
+		IdleTimeout:     30 * time.Minute,
+		AbsoluteTimeout: 24 * time.Hour,
+		Path:            "/",
+		HttpOnly:        true,
+		SameSite:        http.SameSiteLaxMode,
+	}
+}
+
+// sessionData 是会话在存储中的序列化结构
+type sessionData struct {
+	Values   map[string]json.RawMessage   `json:"values,omitempty"`
+	Flashes  map[string][]json.RawMessage `json:"flashes,omitempty"`
+	Created  int64                        `json:"created"`
+	Accessed int64                        `json:"accessed"`
+}
+
+// Session 表示当前请求的会话，修改会在写出响应头之前保存到 Store。
+type Session struct {
+	id      string
+	oldID   string // Regenerate 之前的 ID，提交时从存储中删除。
+	data    sessionData
+	json    *jsonCodec
+	isNew   bool
+	changed bool
+	destroy bool
+	cookie  bool // 请求是否携带了会话 Cookie
+}
+
+// ID 返回会话 ID
+func (s *Session) ID() string {
+	return s.id
+}
+
+// IsNew 报告会话是否在本次请求中创建
+func (s *Session) IsNew() bool {
+	return s.isNew
+}
+
+// Get 将 key 对应的值解码到 ptr，不存在或解码失败时返回 false。
+func (s *Session) Get(key string, ptr any) bool {
+	raw, ok := s.data.Values[key]
+	return ok && s.json.Decode(raw, ptr) == nil
+}
+
+// Set 设置会话值，value 使用引擎的 JSON 配置编码。
+func (s *Session) Set(key string, value any) error {
+	raw, err := s.json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	if s.data.Values == nil {
+		s.data.Values = map[string]json.RawMessage{}
+	}
+	s.data.Values[key] = raw
+	s.changed = true
+	return nil
+}
+
+// Delete 删除会话值
+func (s *Session) Delete(key string) {
+	if _, ok := s.data.Values[key]; ok {
+		delete(s.data.Values, key)
+		s.changed = true
+	}
+}
+
+// Keys 返回所有会话值的键
+func (s *Session) Keys() []string {
+	keys := make([]string, 0, len(s.data.Values))
+	for k := range s.data.Values {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Clear 删除所有会话值和闪存消息
+func (s *Session) Clear() {
+	s.data.Values, s.data.Flashes = nil, nil
+	s.changed = true
+}
+
+// AddFlash 添加一条闪存消息，它在下一次被 Flashes 读取后删除。
+func (s *Session) AddFlash(key string, value any) error {
+	raw, err := s.json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	if s.data.Flashes == nil {
+		s.data.Flashes = map[string][]json.RawMessage{}
+	}
+	s.data.Flashes[key] = append(s.data.Flashes[key], raw)
+	s.changed = true
+	return nil
+}
+
+// Flashes 读取并删除 key 对应的闪存消息，ptr 为切片指针。没有消息时返回 false。
+func (s *Session) Flashes(key string, ptr any) bool {
+	flashes, ok := s.data.Flashes[key]
+	if !ok {
+		return false
+	}
+
+	delete(s.data.Flashes, key)
+	s.changed = true
+
+	raw := make([]byte, 0, 64)
+	raw = append(raw, '[')
+	for i, f := range flashes {
+		if i > 0 {
+			raw = append(raw, ',')
+		}
+		raw = append(raw, f...)
+	}
+	raw = append(raw, ']')
+
+	return s.json.Decode(raw, ptr) == nil
+}
+
+// Regenerate 为会话分配新的 ID 并保留数据，应在登录等权限变化时调用以防止会话固定攻击。
+func (s *Session) Regenerate() {
+	if s.oldID == "" && !s.isNew {
+		s.oldID = s.id
+	}
+	s.id = newSessionID()
+	s.changed = true
+}
+
+// Destroy 删除会话数据并使客户端的会话 Cookie 失效
+func (s *Session) Destroy() {
+	s.data = sessionData{Created: s.data.Created, Accessed: s.data.Accessed}
+	s.destroy = true
+}
+
+// SessionValue 返回会话中 key 对应的 T 类型值
+func SessionValue[T any](s *Session, key string) (v T, ok bool) {
+	ok = s.Get(key, &v)
+	return
+}
+
+// SessionFlashes 读取并删除 key 对应的 T 类型闪存消息
+func SessionFlashes[T any](s *Session, key string) (v []T) {
+	s.Flashes(key, &v)
+	return
+}
+
+// Session 返回当前请求的会话，未启用会话中间件时返回 nil。
+func (c *Ctx) Session() *Session {
+	s, _ := c.Get(sessionKey).(*Session)
+	return s
+}
+
+// Sessions 返回会话中间件
+func Sessions(config ...SessionConfig) Handler {
+	cfg := DefaultSessionConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryStore()
+	}
+
+	return He(func(c *Ctx) error {
+		s := loadSession(c, &cfg)
+		c.Get(sessionKey, s)
+
+		gc := c.Gin()
+		w := &sessionWriter{ResponseWriter: gc.Writer}
+		w.before = func() { saveSession(c, &cfg, s) }
+
+		origin := c.Writer
+		gc.Writer, c.Writer = w, w
+		defer func() {
+			w.commit() // 没有写出响应体时 (如 204) 也要保存会话
+			gc.Writer, c.Writer = origin, origin
+		}()
+
+		return c.Next()
+	})
+}
+
+// loadSession 从 Cookie 和 Store 中加载会话，不存在或已超时时创建新会话。
+func loadSession(c *Ctx, cfg *SessionConfig) *Session {
+	now := time.Now()
+	s := &Session{json: c.engine.json}
+
+	id, err := c.Cookie(cfg.CookieName)
+	if s.cookie = err == nil; s.cookie && validSessionID(id) {
+		if raw, err := cfg.Store.Get(c.Request.Context(), id); err == nil && raw != nil {
+			var data sessionData
+			if c.engine.json.Decode(raw, &data) == nil && !sessionExpired(cfg, &data, now) {
+				s.id, s.data = id, data
+				s.data.Accessed = now.Unix()
+				return s
+			}
+			_ = cfg.Store.Delete(c.Request.Context(), id)
+		}
+	}
+
+	// 不沿用客户端提供的未知 ID，防止会话固定攻击。
+	s.id, s.isNew = newSessionID(), true
+	s.data = sessionData{Created: now.Unix(), Accessed: now.Unix()}
+	return s
+}
+
+// saveSession 将会话保存到 Store 并设置 Cookie，未修改的新会话不会保存。
+func saveSession(c *Ctx, cfg *SessionConfig, s *Session) {
+	ctx := c.Request.Context()
+
+	if s.oldID != "" {
+		_ = cfg.Store.Delete(ctx, s.oldID)
+	}
+
+	if s.destroy {
+		if !s.isNew {
+			_ = cfg.Store.Delete(ctx, s.id)
+		}
+		if s.cookie {
+			c.SetCookie(cfg.CookieName, "", cfg.Path, cfg.Domain, -1, cfg.Secure, cfg.HttpOnly, cfg.SameSite)
+		}
+		return
+	}
+
+	if s.isNew && !s.changed {
+		return
+	}
+
+	// 存储的有效期取空闲超时和剩余绝对超时中较小的一个
+	ttl, maxAge := cfg.IdleTimeout, 0 // maxAge 为 0 时是浏览器会话 Cookie
+	if cfg.AbsoluteTimeout > 0 {
+		remain := time.Until(time.Unix(s.data.Created, 0).Add(cfg.AbsoluteTimeout))
+		if ttl <= 0 || remain < ttl {
+			ttl = remain
+		}
+		maxAge = int(remain.Seconds())
+	}
+	if ttl <= 0 && cfg.AbsoluteTimeout <= 0 { // 没有超时的会话不能立即过期，也不能永久占用存储。
+		ttl = sessionStoreTTL
+	}
+
+	raw, err := c.engine.json.Marshal(s.data)
+	if err == nil {
+		err = cfg.Store.Set(ctx, s.id, raw, ttl)
+	}
+	if err != nil {
+		_ = c.Gin().Error(err) // 由 Logger 记录
+		return
+	}
+
+	// 未修改的会话只需刷新存储中的访问时间
+	if s.changed {
+		c.SetCookie(cfg.CookieName, s.id, cfg.Path, cfg.Domain, maxAge, cfg.Secure, cfg.HttpOnly, cfg.SameSite)
+	}
+}
+
+func sessionExpired(cfg *SessionConfig, data *sessionData, now time.Time) bool {
+	if cfg.IdleTimeout > 0 && now.Sub(time.Unix(data.Accessed, 0)) >= cfg.IdleTimeout {
+		return true
+	}
+	return cfg.AbsoluteTimeout > 0 && now.Sub(time.Unix(data.Created, 0)) >= cfg.AbsoluteTimeout
+}
+
+func newSessionID() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// validSessionID 检查 ID 的格式，避免将任意字符串传递给 Store (如文件路径)。
+func validSessionID(id string) bool {
+	if len(id) != sessionIDLen {
+		return false
+	}
+	return strings.IndexFunc(id, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_')
+	}) < 0
+}
+
+// sessionWriter 在写出响应头之前保存会话，以便设置 Cookie。
+type sessionWriter struct {
+	gin.ResponseWriter
+	before    func()
+	committed bool
+}
+
+func (w *sessionWriter) commit() {
+	if !w.committed {
+		w.committed = true
+		w.before()
+	}
+}
+
+func (w *sessionWriter) Write(b []byte) (int, error) {
+	w.commit()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *sessionWriter) WriteString(s string) (int, error) {
+	w.commit()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *sessionWriter) WriteHeaderNow() {
+	w.commit()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *sessionWriter) Flush() {
+	w.commit()
+	w.ResponseWriter.Flush()
+}
+
+func (w *sessionWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.commit()
+	return w.ResponseWriter.Hijack()
+}
@@ -0,0 +1,122 @@
+package sgin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ttlStore 是记录 ttl 的内存存储
+type ttlStore struct {
+	*MemoryStore
+	mu  sync.Mutex
+	ttl time.Duration
+}
+
+func (s *ttlStore) Set(ctx context.Context, id string, data []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	s.ttl = ttl
+	s.mu.Unlock()
+	return s.MemoryStore.Set(ctx, id, data, ttl)
+}
+
+func sessionEngine(store Store, f func(*SessionConfig)) *Engine {
+	e := testEngine(Config{Session: func(c *SessionConfig) {
+		c.Store = store
+		if f != nil {
+			f(c)
+		}
+	}})
+	e.POST("/login", He(func(c *Ctx) error {
+		return c.Session().Set("uid", 42)
+	}))
+	e.GET("/me", He(func(c *Ctx) error {
+		uid, _ := SessionValue[int](c.Session(), "uid")
+		return c.Send(fmt.Sprint(uid))
+	}))
+	return e
+}
+
+func sessionCookie(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	for _, c := range w.Result().Cookies() {
+		if c.Name == "sgin_session" {
+			return c.Name + "=" + c.Value
+		}
+	}
+	t.Fatal("session cookie not set")
+	return ""
+}
+
+// storeSession 直接写入创建和访问时间为 age 之前的会话
+func storeSession(t *testing.T, store Store, age time.Duration) string {
+	t.Helper()
+	id := newSessionID()
+	at := time.Now().Add(-age).Unix()
+	raw := fmt.Sprintf(`{"values":{"uid":7},"created":%d,"accessed":%d}`, at, at)
+	if err := store.Set(context.Background(), id, []byte(raw), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	return "sgin_session=" + id
+}
+
+func TestSessionRoundTrip(t *testing.T) {
+	store := &ttlStore{MemoryStore: NewMemoryStore()}
+	e := sessionEngine(store, nil)
+
+	cookie := sessionCookie(t, serve(e, http.MethodPost, "/login", nil))
+	if store.ttl <= 0 || store.ttl > 30*time.Minute {
+		t.Fatalf("store ttl = %s", store.ttl)
+	}
+	if w := serve(e, http.MethodGet, "/me", nil, "Cookie", cookie); w.Body.String() != `"42"` {
+		t.Fatalf("uid = %q", w.Body.String())
+	}
+
+	// 未知的会话 ID 不会被沿用
+	w := serve(e, http.MethodGet, "/me", nil, "Cookie", "sgin_session="+newSessionID())
+	if w.Body.String() != `"0"` {
+		t.Fatalf("uid = %q", w.Body.String())
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	for name, f := range map[string]func(*SessionConfig){
+		"idle":     func(c *SessionConfig) { c.IdleTimeout, c.AbsoluteTimeout = time.Minute, 0 },
+		"absolute": func(c *SessionConfig) { c.IdleTimeout, c.AbsoluteTimeout = 0, time.Minute },
+	} {
+		store := NewMemoryStore()
+		e := sessionEngine(store, f)
+
+		if w := serve(e, http.MethodGet, "/me", nil, "Cookie", storeSession(t, store, 10*time.Second)); w.Body.String() != `"7"` {
+			t.Fatalf("%s: fresh session uid = %q", name, w.Body.String())
+		}
+
+		cookie := storeSession(t, store, 2*time.Minute)
+		if w := serve(e, http.MethodGet, "/me", nil, "Cookie", cookie); w.Body.String() != `"0"` {
+			t.Fatalf("%s: expired session uid = %q", name, w.Body.String())
+		}
+		if raw, _ := store.Get(context.Background(), strings.TrimPrefix(cookie, "sgin_session=")); raw != nil {
+			t.Fatalf("%s: expired session was not deleted", name)
+		}
+	}
+}
+
+func TestSessionWithoutTimeouts(t *testing.T) {
+	store := &ttlStore{MemoryStore: NewMemoryStore()}
+	e := sessionEngine(store, func(c *SessionConfig) {
+		c.IdleTimeout, c.AbsoluteTimeout = 0, 0
+	})
+
+	cookie := sessionCookie(t, serve(e, http.MethodPost, "/login", nil))
+	if store.ttl != sessionStoreTTL {
+		t.Fatalf("store ttl = %s, want %s", store.ttl, sessionStoreTTL)
+	}
+	if w := serve(e, http.MethodGet, "/me", nil, "Cookie", cookie); w.Body.String() != `"42"` {
+		t.Fatalf("uid = %q", w.Body.String())
+	}
+}
@@ -0,0 +1,216 @@
+package sgin
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Event 表示一个服务器发送事件 (Server-Sent Event)
+type Event[T any] struct {
+	ID    string        // 事件 ID，客户端重连时通过 Last-Event-ID 请求头带回。
+	Name  string        // 事件名称，为空时客户端触发默认的 message 事件。
+	Data  T             // 事件数据，字符串原样发送，其他类型编码为 JSON。
+	Retry time.Duration // 建议客户端的重连间隔，为 0 时不发送。
+}
+
+// SSEConfig SSE 处理器配置
+type SSEConfig struct {
+	Heartbeat time.Duration // 心跳注释的发送间隔，为 0 时不发送。
+	Retry     time.Duration // 连接建立时发送给客户端的重连间隔，为 0 时不发送。
+}
+
+// DefaultSSEConfig 返回默认的 SSE 配置
+func DefaultSSEConfig() SSEConfig {
+	return SSEConfig{Heartbeat: 15 * time.Second}
+}
+
+// SSE 创建一个强类型的服务器发送事件处理器 (支持 OpenAPI)
+//
+// 处理器向 events 发送事件，返回后事件流结束，不要自行关闭 events。
+// 客户端断开后，后续事件会被丢弃，处理器应通过 c.Request.Context() 感知并尽快返回。
+// 断线重连时可使用 c.LastEventID() 获取客户端最后收到的事件 ID。
+// 事件数据编码失败时跳过该事件，第一个编码错误在处理器返回后与处理器返回的错误一样处理。
+func SSE[I any, T any](f func(c *Ctx, in I, events chan<- Event[T]) error, config ...SSEConfig) Handler {
+	cfg := DefaultSSEConfig()
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	h := H(func(c *Ctx, in I) (any, error) {
+		s := &sseWriter{c: c, cfg: cfg}
+		events := make(chan Event[T])
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+			sseLoop(s, events)
+		}()
+
+		err := f(c, in, events)
+		close(events)
+		<-done
+
+		if s.err != nil {
+			_ = c.ctx.Error(s.err) // 由 Logger 记录
+			if err == nil {
+				err = s.err
+			}
+		}
+
+		if err != nil && s.started {
+			// 事件流已开始，无法再修改状态码，以 error 事件通知客户端。
+			if !s.closed {
+				_ = s.write("", "error", err.Error(), 0)
+			}
+			return nil, nil
+		}
+
+		c.ctx.Abort()
+		return nil, err
+	})
+
+	if a, ok := hMeta.Get(h); ok {
+		a.Out, a.Stream = reflect.TypeFor[T](), true
+		a.Media = []string{MIMETextEventStream}
+		a.Func = funcName(f)
+	}
+
+	return h
+}
+
+// sseWriter 将事件按 text/event-stream 格式写入响应
+type sseWriter struct {
+	c       *Ctx
+	cfg     SSEConfig
+	started bool  // 是否已写入响应头
+	closed  bool  // 客户端是否已断开
+	err     error // 第一个事件数据的编码错误
+}
+
+// sseLoop 持续写出事件和心跳，直到 events 被关闭。客户端断开后仍会读取并丢弃事件，避免处理器阻塞。
+func sseLoop[T any](s *sseWriter, events <-chan Event[T]) {
+	var heartbeat <-chan time.Time
+	if s.cfg.Heartbeat > 0 {
+		ticker := time.NewTicker(s.cfg.Heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	gone := s.c.Request.Context().Done()
+
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if s.closed {
+				continue
+			}
+			text, err := s.encode(ev.ID, ev.Name, ev.Data, ev.Retry)
+			if err != nil { // 编码失败只跳过当前事件
+				if s.err == nil {
+					s.err = err
+				}
+				continue
+			}
+			if s.send(text) != nil {
+				s.closed = true
+			}
+		case <-heartbeat:
+			if !s.closed && s.ping() != nil {
+				s.closed = true
+			}
+		case <-gone:
+			s.closed, gone, heartbeat = true, nil, nil
+		}
+	}
+}
+
+// start 写入 SSE 响应头和可选的 retry 提示
+func (s *sseWriter) start() error {
+	if s.started {
+		return nil
+	}
+	s.started = true
+
+	h := s.c.Writer.Header()
+	h.Set(HeaderContentType, MIMETextEventStream+"; charset=utf-8")
+	h.Set(HeaderCacheControl, "no-cache")
+	h.Set(HeaderConnection, "keep-alive")
+	h.Set("X-Accel-Buffering", "no") // 禁止 Nginx 缓冲
+	s.c.Writer.WriteHeader(http.StatusOK)
+
+	if s.cfg.Retry > 0 {
+		_, err := fmt.Fprintf(s.c.Writer, "retry: %d\n\n", s.cfg.Retry.Milliseconds())
+		return err
+	}
+
+	return nil
+}
+
+// write 写入一个事件并刷新
+func (s *sseWriter) write(id, name string, data any, retry time.Duration) error {
+	text, err := s.encode(id, name, data, retry)
+	if err != nil {
+		return err
+	}
+	return s.send(text)
+}
+
+// encode 将事件编码为 text/event-stream 格式
+func (s *sseWriter) encode(id, name string, data any, retry time.Duration) (string, error) {
+	var sb strings.Builder
+	if id != "" {
+		sb.WriteString("id: " + strings.NewReplacer("\n", "", "\r", "").Replace(id) + "\n")
+	}
+	if name != "" {
+		sb.WriteString("event: " + strings.NewReplacer("\n", "", "\r", "").Replace(name) + "\n")
+	}
+	if retry > 0 {
+		printf(&sb, "retry: %d\n", retry.Milliseconds())
+	}
+
+	text, ok := data.(string)
+	if !ok {
+		b, err := s.c.engine.json.Marshal(data)
+		if err != nil {
+			return "", err
+		}
+		text = string(b)
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		sb.WriteString("data: " + line + "\n")
+	}
+	sb.WriteString("\n")
+
+	return sb.String(), nil
+}
+
+// send 写入已编码的事件并刷新
+func (s *sseWriter) send(text string) error {
+	if err := s.start(); err != nil {
+		return err
+	}
+	return s.flush(text)
+}
+
+// ping 写入一个心跳注释
+func (s *sseWriter) ping() error {
+	if err := s.start(); err != nil {
+		return err
+	}
+	return s.flush(": ping\n\n")
+}
+
+func (s *sseWriter) flush(text string) error {
+	if _, err := s.c.Writer.WriteString(text); err != nil {
+		return err
+	}
+	s.c.Writer.Flush()
+	return nil
+}
@@ -0,0 +1,140 @@
+package sgin
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type sseOrder struct {
+	ID int `json:"id"`
+}
+
+func TestSSEFraming(t *testing.T) {
+	e := testEngine()
+	e.GET("/events", SSE(func(c *Ctx, _ struct{}, events chan<- Event[any]) error {
+		events <- Event[any]{ID: c.LastEventID() + "1", Name: "order", Data: sseOrder{ID: 7}}
+		events <- Event[any]{ID: "2\n3", Name: "note\r\nx", Data: "line1\nline2", Retry: 2 * time.Second}
+		events <- Event[any]{Data: ""}
+		return nil
+	}, SSEConfig{Retry: 3 * time.Second}))
+
+	w := serve(e, http.MethodGet, "/events", nil, HeaderLastEventID, "a")
+	expectStatus(t, w, http.StatusOK)
+	if ct := w.Header().Get(HeaderContentType); ct != MIMETextEventStream+"; charset=utf-8" {
+		t.Fatalf("content type = %s", ct)
+	}
+	if w.Header().Get(HeaderCacheControl) != "no-cache" {
+		t.Fatalf("cache control = %s", w.Header().Get(HeaderCacheControl))
+	}
+
+	want := "retry: 3000\n\n" +
+		"id: a1\nevent: order\ndata: {\"id\":7}\n\n" +
+		"id: 23\nevent: notex\nretry: 2000\ndata: line1\ndata: line2\n\n" +
+		"data: \n\n"
+	if w.Body.String() != want {
+		t.Fatalf("body =\n%q\nwant\n%q", w.Body.String(), want)
+	}
+}
+
+func TestSSEErrors(t *testing.T) {
+	e := testEngine()
+	e.GET("/before", SSE(func(c *Ctx, _ struct{}, events chan<- Event[int]) error {
+		return ErrBadRequest("bad cursor")
+	}))
+	e.GET("/after", SSE(func(c *Ctx, _ struct{}, events chan<- Event[int]) error {
+		events <- Event[int]{Data: 1}
+		return errors.New("upstream closed")
+	}))
+	e.GET("/marshal", SSE(func(c *Ctx, _ struct{}, events chan<- Event[any]) error {
+		events <- Event[any]{Data: 1}
+		events <- Event[any]{Data: func() {}} // 无法编码为 JSON
+		events <- Event[any]{Data: 2}
+		return nil
+	}))
+	e.GET("/marshal-first", SSE(func(c *Ctx, _ struct{}, events chan<- Event[any]) error {
+		events <- Event[any]{Data: make(chan int)}
+		return nil
+	}))
+
+	// 事件流开始前的错误交由 ErrorHandler 处理
+	w := serve(e, http.MethodGet, "/before", nil)
+	expectStatus(t, w, http.StatusBadRequest)
+	if strings.HasPrefix(w.Header().Get(HeaderContentType), MIMETextEventStream) {
+		t.Fatal("error response uses the event stream content type")
+	}
+
+	// 开始后以 error 事件通知
+	w = serve(e, http.MethodGet, "/after", nil)
+	if want := "data: 1\n\nevent: error\ndata: upstream closed\n\n"; w.Body.String() != want {
+		t.Fatalf("body = %q", w.Body.String())
+	}
+
+	// 编码失败的事件被跳过，后续事件照常发送，错误在结束时报告。
+	w = serve(e, http.MethodGet, "/marshal", nil)
+	body := w.Body.String()
+	if !strings.HasPrefix(body, "data: 1\n\ndata: 2\n\nevent: error\n") {
+		t.Fatalf("body = %q", body)
+	}
+
+	expectStatus(t, serve(e, http.MethodGet, "/marshal-first", nil), http.StatusInternalServerError)
+}
+
+func TestSSEHeartbeat(t *testing.T) {
+	e := testEngine()
+	e.GET("/events", SSE(func(c *Ctx, _ struct{}, events chan<- Event[int]) error {
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	}, SSEConfig{Heartbeat: 10 * time.Millisecond}))
+
+	if w := serve(e, http.MethodGet, "/events", nil); !strings.HasPrefix(w.Body.String(), ": ping\n\n") {
+		t.Fatalf("body = %q", w.Body.String())
+	}
+}
+
+func TestSSEClientDisconnect(t *testing.T) {
+	returned := make(chan int, 1)
+	e := testEngine()
+	e.GET("/events", SSE(func(c *Ctx, _ struct{}, events chan<- Event[int]) error {
+		sent := 0
+		for i := 0; ; i++ {
+			select {
+			case <-c.Request.Context().Done():
+				returned <- sent
+				return nil
+			case events <- Event[int]{Data: i}: // 断开后事件被丢弃，发送不会阻塞
+				sent++
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}))
+	srv := httptest.NewServer(e.Gin())
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil || line != "data: 0\n" {
+		t.Fatalf("first line = %q, %v", line, err)
+	}
+	cancel()
+	_ = resp.Body.Close()
+
+	select {
+	case n := <-returned:
+		if n == 0 {
+			t.Fatal("no events sent")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler did not return after the client disconnected")
+	}
+}
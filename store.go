@@ -0,0 +1,191 @@
+package sgin
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// storeGCInterval 存储清理过期会话的最短间隔
+const storeGCInterval = 5 * time.Minute
+
+// Store 是会话数据的存储后端，实现需要并发安全。
+type Store interface {
+	Get(ctx context.Context, id string) ([]byte, error)                       // 获取会话数据，不存在或已过期时返回 nil, nil。
+	Set(ctx context.Context, id string, data []byte, ttl time.Duration) error // 保存会话数据，ttl 后过期。
+	Delete(ctx context.Context, id string) error                              // 删除会话数据
+}
+
+type memoryItem struct {
+	data    []byte
+	expires time.Time
+}
+
+// MemoryStore 将会话保存在进程内存中，适用于单实例部署和开发环境。
+type MemoryStore struct {
+	mu     sync.RWMutex
+	items  map[string]memoryItem
+	lastGC time.Time
+}
+
+// NewMemoryStore 创建内存会话存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{items: map[string]memoryItem{}, lastGC: time.Now()}
+}
+
+func (s *MemoryStore) Get(_ context.Context, id string) ([]byte, error) {
+	s.mu.RLock()
+	item, ok := s.items[id]
+	s.mu.RUnlock()
+
+	if !ok || time.Now().After(item.expires) {
+		return nil, nil
+	}
+	return item.data, nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, id string, data []byte, ttl time.Duration) error {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items[id] = memoryItem{data: data, expires: now.Add(ttl)}
+	if now.Sub(s.lastGC) >= storeGCInterval { // 顺带清理过期的会话
+		s.lastGC = now
+		for k, item := range s.items {
+			if now.After(item.expires) {
+				delete(s.items, k)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	delete(s.items, id)
+	s.mu.Unlock()
+	return nil
+}
+
+// FileStore 将每个会话保存为目录中的一个文件，文件内容为 [8 字节过期时间戳 || 数据]。
+type FileStore struct {
+	dir    string
+	mu     sync.Mutex
+	lastGC time.Time
+}
+
+// NewFileStore 创建文件会话存储，dir 不存在时自动创建。
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir, lastGC: time.Now()}, nil
+}
+
+func (s *FileStore) path(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return "", errors.New("sgin: invalid session id")
+	}
+	return filepath.Join(s.dir, "sess_"+id), nil
+}
+
+func (s *FileStore) Get(_ context.Context, id string) ([]byte, error) {
+	name, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) < 8 || time.Now().Unix() >= int64(binary.BigEndian.Uint64(b)) {
+		_ = os.Remove(name)
+		return nil, nil
+	}
+
+	return b[8:], nil
+}
+
+func (s *FileStore) Set(_ context.Context, id string, data []byte, ttl time.Duration) error {
+	name, err := s.path(id)
+	if err != nil {
+		return err
+	}
+
+	b := binary.BigEndian.AppendUint64(make([]byte, 0, 8+len(data)), uint64(time.Now().Add(ttl).Unix()))
+	b = append(b, data...)
+
+	// 先写入临时文件再重命名，避免并发读取到不完整的数据。
+	tmp, err := os.CreateTemp(s.dir, "tmp_")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(b); err == nil {
+		err = tmp.Close()
+	} else {
+		_ = tmp.Close()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), name)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	s.gc()
+	return nil
+}
+
+func (s *FileStore) Delete(_ context.Context, id string) error {
+	name, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(name); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// gc 定期删除过期的会话文件
+func (s *FileStore) gc() {
+	s.mu.Lock()
+	now := time.Now()
+	if now.Sub(s.lastGC) < storeGCInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastGC = now
+	s.mu.Unlock()
+
+	entries, _ := os.ReadDir(s.dir)
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "sess_") {
+			continue
+		}
+		name := filepath.Join(s.dir, entry.Name())
+		if f, err := os.Open(name); err == nil {
+			var head [8]byte
+			_, err = f.Read(head[:])
+			_ = f.Close()
+			if err != nil || now.Unix() >= int64(binary.BigEndian.Uint64(head[:])) {
+				_ = os.Remove(name)
+			}
+		}
+	}
+}
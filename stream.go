@@ -0,0 +1,172 @@
+package sgin
+
+import (
+	"iter"
+	"reflect"
+	"time"
+)
+
+// streamFlushInterval 流式响应的最长刷新间隔
+const streamFlushInterval = 100 * time.Millisecond
+
+var errorType = reflect.TypeFor[error]()
+
+// streamElem 检查 t 是否为 iter.Seq[T]、iter.Seq2[T, error] 或 <-chan T，并返回元素类型 T。
+func streamElem(t reflect.Type) (reflect.Type, bool) {
+	switch t.Kind() {
+	case reflect.Chan:
+		if t.ChanDir()&reflect.RecvDir != 0 {
+			return t.Elem(), true
+		}
+	case reflect.Func:
+		if t.NumIn() != 1 || t.NumOut() != 0 {
+			return nil, false
+		}
+		yield := t.In(0)
+		if yield.Kind() != reflect.Func || yield.NumOut() != 1 || yield.Out(0).Kind() != reflect.Bool {
+			return nil, false
+		}
+		switch yield.NumIn() {
+		case 1:
+			return yield.In(0), true
+		case 2:
+			if yield.In(1) == errorType {
+				return yield.In(0), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// asStream 将 iter.Seq[T]、iter.Seq2[T, error] 或 <-chan T 转换为统一的迭代器，
+// 迭代器在 done 关闭后停止。live 标识数据源是否为通道 (实时数据，每个元素都需要立即刷新)。
+func asStream(data any, done <-chan struct{}) (seq iter.Seq2[any, error], live bool, ok bool) {
+	v := reflect.ValueOf(data)
+	if k := v.Kind(); (k != reflect.Func && k != reflect.Chan) || v.IsNil() {
+		return nil, false, false
+	}
+
+	if _, ok = streamElem(v.Type()); !ok {
+		return nil, false, false
+	}
+
+	if v.Kind() == reflect.Chan {
+		return func(yield func(any, error) bool) {
+			cases := []reflect.SelectCase{
+				{Dir: reflect.SelectRecv, Chan: v},
+				{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(done)},
+			}
+			for {
+				chosen, item, ok := reflect.Select(cases)
+				if chosen == 1 || !ok || !yield(item.Interface(), nil) {
+					return
+				}
+			}
+		}, true, true
+	}
+
+	yieldType := v.Type().In(0)
+	return func(yield func(any, error) bool) {
+		fn := reflect.MakeFunc(yieldType, func(args []reflect.Value) []reflect.Value {
+			var err error
+			if len(args) == 2 && !args[1].IsNil() {
+				err = args[1].Interface().(error)
+			}
+
+			ok := false
+			select {
+			case <-done:
+			default:
+				ok = yield(args[0].Interface(), err)
+			}
+			return []reflect.Value{reflect.ValueOf(ok)}
+		})
+		v.Call([]reflect.Value{fn})
+	}, false, true
+}
+
+// sendStream 根据 Accept 将迭代器以 NDJSON、JSON 数组或 SSE 的形式流式写出
+func (c *Ctx) sendStream(seq iter.Seq2[any, error], live bool) {
+	gc := c.ctx
+	gc.Abort()
+
+	format := gc.NegotiateFormat(MIMEJSON, MIMENDJSON, MIMETextEventStream)
+	sse := &sseWriter{c: c}
+
+	var (
+		started   bool
+		count     int
+		lastFlush = time.Now()
+	)
+
+	// start 在写出第一个元素前设置响应头，以便在此之前发生的错误仍可交由 ErrorHandler 处理。
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+
+		switch format {
+		case MIMETextEventStream:
+			_ = sse.start()
+			return
+		case MIMENDJSON:
+			c.Content(MIMENDJSON)
+		default:
+			c.Content(MIMEJSON + "; charset=utf-8")
+		}
+
+		c.Writer.WriteHeader(c.StatusCode())
+		if format != MIMENDJSON {
+			_, _ = c.Writer.WriteString("[")
+		}
+	}
+
+	for item, err := range seq {
+		if err != nil {
+			if !started {
+				_ = c.engine.cfg.ErrorHandler(c, err)
+				return
+			}
+			_ = gc.Error(err) // 由 Logger 记录
+			if format == MIMETextEventStream {
+				_ = sse.write("", "error", err.Error(), 0)
+			}
+			return // JSON 数组不会闭合，客户端可据此判断流不完整。
+		}
+
+		start()
+		if format == MIMETextEventStream {
+			if sse.write("", "", item, 0) != nil {
+				return
+			}
+			continue
+		}
+
+		b, err := c.engine.json.Marshal(item)
+		if err != nil {
+			_ = gc.Error(err)
+			return
+		}
+
+		if format == MIMENDJSON {
+			b = append(b, '\n')
+		} else if count > 0 {
+			b = append([]byte{','}, b...)
+		}
+
+		if _, err = c.Writer.Write(b); err != nil {
+			return // 客户端已断开
+		}
+
+		if count++; live || time.Since(lastFlush) >= streamFlushInterval {
+			c.Writer.Flush()
+			lastFlush = time.Now()
+		}
+	}
+
+	if start(); format != MIMENDJSON && format != MIMETextEventStream {
+		_, _ = c.Writer.WriteString("]")
+	}
+	c.Writer.Flush()
+}
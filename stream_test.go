@@ -0,0 +1,181 @@
+package sgin
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type streamItem struct {
+	ID int `json:"id"`
+}
+
+func streamItems(n int) iter.Seq[streamItem] {
+	return func(yield func(streamItem) bool) {
+		for i := 1; i <= n; i++ {
+			if !yield(streamItem{ID: i}) {
+				return
+			}
+		}
+	}
+}
+
+// streamFailAt 在第 n 个元素时返回错误
+func streamFailAt(n int) iter.Seq2[streamItem, error] {
+	return func(yield func(streamItem, error) bool) {
+		for i := 1; ; i++ {
+			if i == n {
+				yield(streamItem{}, ErrServiceUnavailable("upstream closed"))
+				return
+			}
+			if !yield(streamItem{ID: i}, nil) {
+				return
+			}
+		}
+	}
+}
+
+func streamEngine() *Engine {
+	e := testEngine(Config{OpenAPI: NewAPI()})
+	e.GET("/items", H(func(c *Ctx, _ struct{}) (iter.Seq[streamItem], error) {
+		return streamItems(3), nil
+	}))
+	e.GET("/empty", H(func(c *Ctx, _ struct{}) (iter.Seq[streamItem], error) {
+		return streamItems(0), nil
+	}))
+	e.GET("/fail-first", H(func(c *Ctx, _ struct{}) (iter.Seq2[streamItem, error], error) {
+		return streamFailAt(1), nil
+	}))
+	e.GET("/fail-later", H(func(c *Ctx, _ struct{}) (iter.Seq2[streamItem, error], error) {
+		return streamFailAt(3), nil
+	}))
+	e.GET("/chan", H(func(c *Ctx, _ struct{}) (<-chan streamItem, error) {
+		ch := make(chan streamItem, 2)
+		ch <- streamItem{ID: 1}
+		ch <- streamItem{ID: 2}
+		close(ch)
+		return ch, nil
+	}))
+	return e
+}
+
+func TestStreamFormats(t *testing.T) {
+	e := streamEngine()
+	tests := []struct {
+		target, accept, ct, body string
+	}{
+		{"/items", "", MIMEJSON + "; charset=utf-8", `[{"id":1},{"id":2},{"id":3}]`},
+		{"/items", MIMENDJSON, MIMENDJSON, "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n"},
+		{"/items", MIMETextEventStream, MIMETextEventStream + "; charset=utf-8", "data: {\"id\":1}\n\ndata: {\"id\":2}\n\ndata: {\"id\":3}\n\n"},
+		{"/empty", "", MIMEJSON + "; charset=utf-8", `[]`},
+		{"/empty", MIMENDJSON, MIMENDJSON, ""},
+		{"/chan", MIMENDJSON, MIMENDJSON, "{\"id\":1}\n{\"id\":2}\n"},
+	}
+
+	for _, tt := range tests {
+		w := serve(e, http.MethodGet, tt.target, nil, HeaderAccept, tt.accept)
+		expectStatus(t, w, http.StatusOK)
+		if ct := w.Header().Get(HeaderContentType); ct != tt.ct {
+			t.Errorf("%s (%s): content type = %s", tt.target, tt.accept, ct)
+		}
+		if w.Body.String() != tt.body {
+			t.Errorf("%s (%s): body = %q, want %q", tt.target, tt.accept, w.Body.String(), tt.body)
+		}
+	}
+
+	// JSON 中表现为元素数组
+	s := e.Spec().Paths["/items"].Get.Responses["200"].Content[MIMEJSON].Schema
+	if s.Type != TypeArray || s.Items == nil || s.Items.Ref == "" {
+		t.Fatalf("schema = %+v", s)
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	e := streamEngine()
+
+	// 写出第一个元素之前的错误交由 ErrorHandler 处理
+	w := serve(e, http.MethodGet, "/fail-first", nil, HeaderAccept, MIMENDJSON)
+	expectStatus(t, w, http.StatusServiceUnavailable)
+	if ct := w.Header().Get(HeaderContentType); ct == MIMENDJSON {
+		t.Fatalf("content type = %s", ct)
+	}
+
+	// 开始后出错时停止写出，JSON 数组不闭合。
+	for accept, want := range map[string]string{
+		MIMEJSON:            `[{"id":1},{"id":2}`,
+		MIMENDJSON:          "{\"id\":1}\n{\"id\":2}\n",
+		MIMETextEventStream: "data: {\"id\":1}\n\ndata: {\"id\":2}\n\nevent: error\ndata: upstream closed\n\n",
+	} {
+		w = serve(e, http.MethodGet, "/fail-later", nil, HeaderAccept, accept)
+		expectStatus(t, w, http.StatusOK)
+		if w.Body.String() != want {
+			t.Errorf("%s: body = %q, want %q", accept, w.Body.String(), want)
+		}
+	}
+}
+
+func TestStreamCancel(t *testing.T) {
+	stopped := make(chan int, 1)
+	e := testEngine()
+	e.GET("/items", H(func(c *Ctx, _ struct{}) (iter.Seq[streamItem], error) {
+		return func(yield func(streamItem) bool) {
+			for i := 1; ; i++ {
+				if !yield(streamItem{ID: i}) {
+					stopped <- i
+					return
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}, nil
+	}))
+	srv := httptest.NewServer(e.Gin())
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/items", nil)
+	req.Header.Set(HeaderAccept, MIMENDJSON)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line, err := bufio.NewReader(resp.Body).ReadString('\n'); err != nil || line != "{\"id\":1}\n" {
+		t.Fatalf("first line = %q, %v", line, err)
+	}
+	cancel()
+	_ = resp.Body.Close()
+
+	select {
+	case n := <-stopped:
+		if n < 1 {
+			t.Fatalf("stopped at %d", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("iterator was not stopped after the client disconnected")
+	}
+}
+
+func TestStreamElem(t *testing.T) {
+	for _, tt := range []struct {
+		v    any
+		want bool
+	}{
+		{streamItems(1), true},
+		{streamFailAt(1), true},
+		{make(<-chan int), true},
+		{make(chan<- int), false},
+		{func(yield func(int, string) bool) {}, false},
+		{func() {}, false},
+		{errors.New("x"), false},
+	} {
+		typ := reflect.TypeOf(tt.v)
+		if _, ok := streamElem(typ); ok != tt.want {
+			t.Errorf("streamElem(%s) = %v, want %v", typ, ok, tt.want)
+		}
+	}
+}
@@ -0,0 +1,532 @@
+package sgin
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// wsGUID 是 RFC 6455 中用于计算 Sec-WebSocket-Accept 的固定 GUID
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket 关闭码 (RFC 6455 7.4.1)
+const (
+	WSCloseNormal          = 1000
+	WSCloseGoingAway       = 1001
+	WSCloseProtocolError   = 1002
+	WSCloseUnsupportedData = 1003
+	WSCloseNoStatus        = 1005
+	WSCloseAbnormal        = 1006
+	WSCloseInvalidPayload  = 1007
+	WSClosePolicyViolation = 1008
+	WSCloseMessageTooBig   = 1009
+	WSCloseInternalError   = 1011
+)
+
+// WebSocket 帧操作码
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// WSCloseError 表示连接已被关闭，Code 为对端发送或本端使用的关闭码。
+type WSCloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *WSCloseError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("websocket: close %d", e.Code)
+	}
+	return fmt.Sprintf("websocket: close %d (%s)", e.Code, e.Reason)
+}
+
+// WSConfig WebSocket 处理器配置
+type WSConfig struct {
+	Subprotocols []string                // 服务端支持的子协议，按优先顺序排列。
+	CheckOrigin  func(c *Ctx) bool       // 校验 Origin 请求头，默认仅允许同源请求。
+	ReadLimit    int64                   // 单条消息的最大字节数，超出时以 1009 关闭连接，<= 0 时使用默认值。
+	PingInterval time.Duration           // 发送 ping 的间隔，超过两个间隔未收到任何帧则断开，为 0 时使用默认值，< 0 时不发送。
+	WriteTimeout time.Duration           // 写入单个帧的超时时间，为 0 时使用默认值，< 0 时不限制。
+	OnClose      func(c *Ctx, err error) // 连接关闭时的回调，err 为关闭原因。
+}
+
+// DefaultWSConfig 返回默认的 WebSocket 配置
+func DefaultWSConfig() WSConfig {
+	return WSConfig{
+		ReadLimit:    1 << 20,
+		PingInterval: 30 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+}
+
+// withDefaults 使用默认值填充 cfg 中的零值字段
+func (cfg WSConfig) withDefaults() WSConfig {
+	def := DefaultWSConfig()
+	if cfg.ReadLimit <= 0 { // 不允许无限制，否则客户端声明的帧长度会导致任意大小的内存分配。
+		cfg.ReadLimit = def.ReadLimit
+	}
+	if cfg.PingInterval == 0 {
+		cfg.PingInterval = def.PingInterval
+	}
+	if cfg.WriteTimeout == 0 {
+		cfg.WriteTimeout = def.WriteTimeout
+	}
+	return cfg
+}
+
+// WSConn 是一个强类型的 WebSocket 连接，I 为接收的消息类型，O 为发送的消息类型。
+// 消息以 JSON 文本帧传输，Write 可以在多个 goroutine 中并发调用。
+type WSConn[I any, O any] struct {
+	conn        net.Conn
+	br          *bufio.Reader
+	cfg         *WSConfig
+	json        *jsonCodec
+	subprotocol string
+
+	mu        sync.Mutex // 保护写入
+	closeSent bool
+}
+
+// Subprotocol 返回协商后的子协议
+func (ws *WSConn[I, O]) Subprotocol() string {
+	return ws.subprotocol
+}
+
+// Read 读取并解码下一条消息。对端关闭连接时返回 *WSCloseError。
+func (ws *WSConn[I, O]) Read() (in I, err error) {
+	_, data, err := ws.ReadMessage()
+	if err != nil {
+		return in, err
+	}
+
+	if err = ws.json.Decode(data, &in); err != nil {
+		_ = ws.Close(WSCloseUnsupportedData, "invalid message")
+		return in, err
+	}
+
+	return in, nil
+}
+
+// Write 将 out 编码为 JSON 并以文本帧发送
+func (ws *WSConn[I, O]) Write(out O) error {
+	data, err := ws.json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	return ws.WriteMessage(false, data)
+}
+
+// ReadMessage 读取下一条原始消息，isBinary 标识是否为二进制帧。
+// ping、pong 等控制帧会被自动处理。
+func (ws *WSConn[I, O]) ReadMessage() (isBinary bool, data []byte, err error) {
+	var op byte // 当前消息的操作码，为 0 表示尚未开始。
+
+	for {
+		fin, opcode, payload, err := ws.readFrame(ws.cfg.ReadLimit - int64(len(data)))
+		if err != nil {
+			return false, nil, ws.fail(err)
+		}
+
+		switch opcode {
+		case wsOpPing:
+			if err = ws.writeFrame(wsOpPong, payload); err != nil {
+				return false, nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			ce, ok := parseClose(payload)
+			if !ok {
+				return false, nil, ws.fail(&WSCloseError{Code: WSCloseProtocolError, Reason: "invalid close frame"})
+			}
+			if ce.Code == WSCloseNoStatus {
+				_ = ws.Close(WSCloseNormal, "")
+			} else {
+				_ = ws.Close(ce.Code, "") // 回应相同的关闭码
+			}
+			return false, nil, ce
+		case wsOpText, wsOpBinary:
+			if op != 0 {
+				return false, nil, ws.fail(&WSCloseError{Code: WSCloseProtocolError, Reason: "unexpected data frame"})
+			}
+			op, data = opcode, payload
+		case wsOpContinuation:
+			if op == 0 {
+				return false, nil, ws.fail(&WSCloseError{Code: WSCloseProtocolError, Reason: "unexpected continuation frame"})
+			}
+			data = append(data, payload...)
+		default:
+			return false, nil, ws.fail(&WSCloseError{Code: WSCloseProtocolError, Reason: "unknown opcode"})
+		}
+
+		if !fin {
+			continue
+		}
+
+		if op == wsOpText && !utf8.Valid(data) {
+			return false, nil, ws.fail(&WSCloseError{Code: WSCloseInvalidPayload})
+		}
+
+		return op == wsOpBinary, data, nil
+	}
+}
+
+// WriteMessage 发送一条原始消息
+func (ws *WSConn[I, O]) WriteMessage(isBinary bool, data []byte) error {
+	if isBinary {
+		return ws.writeFrame(wsOpBinary, data)
+	}
+	return ws.writeFrame(wsOpText, data)
+}
+
+// Ping 发送一个 ping 帧
+func (ws *WSConn[I, O]) Ping(data ...byte) error {
+	return ws.writeFrame(wsOpPing, data)
+}
+
+// Close 发送关闭帧，重复调用时忽略。关闭后底层连接由处理器返回时释放。
+func (ws *WSConn[I, O]) Close(code int, reason string) error {
+	ws.mu.Lock()
+	if ws.closeSent {
+		ws.mu.Unlock()
+		return nil
+	}
+	ws.closeSent = true
+	ws.mu.Unlock()
+
+	if len(reason) > 123 { // 控制帧负载不能超过 125 字节，在字符边界截断。
+		i := 123
+		for i > 0 && !utf8.RuneStart(reason[i]) {
+			i--
+		}
+		reason = reason[:i]
+	}
+
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	return ws.writeRaw(wsOpClose, append(payload, reason...))
+}
+
+// parseClose 解析对端的关闭帧负载，关闭码不允许在帧中发送 (如 1005、1006) 或原因不是有效的 UTF-8 时返回 false。
+func parseClose(payload []byte) (*WSCloseError, bool) {
+	switch len(payload) {
+	case 0:
+		return &WSCloseError{Code: WSCloseNoStatus}, true
+	case 1:
+		return nil, false
+	}
+
+	ce := &WSCloseError{Code: int(binary.BigEndian.Uint16(payload)), Reason: string(payload[2:])}
+	return ce, validCloseCode(ce.Code) && utf8.ValidString(ce.Reason)
+}
+
+// validCloseCode 报告 code 是否可以出现在关闭帧中 (RFC 6455 7.4)：
+// 1000-1003、1007-1014 为协议定义的关闭码，3000-4999 供库和应用使用。
+func validCloseCode(code int) bool {
+	switch {
+	case code >= 1000 && code <= 1003, code >= 1007 && code <= 1014:
+		return true
+	default:
+		return code >= 3000 && code <= 4999
+	}
+}
+
+// fail 在协议错误时以对应的关闭码关闭连接
+func (ws *WSConn[I, O]) fail(err error) error {
+	var ce *WSCloseError
+	if errors.As(err, &ce) {
+		_ = ws.Close(ce.Code, ce.Reason)
+	}
+	return err
+}
+
+// readFrame 读取一个完整的帧并解除掩码
+func (ws *WSConn[I, O]) readFrame(limit int64) (fin bool, op byte, payload []byte, err error) {
+	var h [2]byte
+	if _, err = io.ReadFull(ws.br, h[:]); err != nil {
+		return
+	}
+
+	if ws.cfg.PingInterval > 0 { // 收到任何帧都说明连接仍然存活
+		_ = ws.conn.SetReadDeadline(time.Now().Add(2 * ws.cfg.PingInterval))
+	}
+
+	fin, op = h[0]&0x80 != 0, h[0]&0x0f
+	if h[0]&0x70 != 0 {
+		return fin, op, nil, &WSCloseError{Code: WSCloseProtocolError, Reason: "reserved bits set"}
+	}
+	if h[1]&0x80 == 0 {
+		return fin, op, nil, &WSCloseError{Code: WSCloseProtocolError, Reason: "client frame not masked"}
+	}
+
+	n := uint64(h[1] & 0x7f)
+	switch n {
+	case 126:
+		var b [2]byte
+		if _, err = io.ReadFull(ws.br, b[:]); err != nil {
+			return
+		}
+		n = uint64(binary.BigEndian.Uint16(b[:]))
+	case 127:
+		var b [8]byte
+		if _, err = io.ReadFull(ws.br, b[:]); err != nil {
+			return
+		}
+		n = binary.BigEndian.Uint64(b[:])
+	}
+
+	if op >= wsOpClose && (!fin || n > 125) {
+		return fin, op, nil, &WSCloseError{Code: WSCloseProtocolError, Reason: "invalid control frame"}
+	}
+	if op < wsOpClose && n > uint64(max(limit, 0)) { // 在分配内存之前拒绝超出限制的帧
+		return fin, op, nil, &WSCloseError{Code: WSCloseMessageTooBig}
+	}
+
+	var mask [4]byte
+	if _, err = io.ReadFull(ws.br, mask[:]); err != nil {
+		return
+	}
+
+	payload = make([]byte, n)
+	if _, err = io.ReadFull(ws.br, payload); err != nil {
+		return
+	}
+
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return fin, op, payload, nil
+}
+
+// writeFrame 写入一个不带掩码的帧 (服务端帧不需要掩码)
+func (ws *WSConn[I, O]) writeFrame(op byte, payload []byte) error {
+	ws.mu.Lock()
+	closed := ws.closeSent
+	ws.mu.Unlock()
+
+	if closed {
+		return &WSCloseError{Code: WSCloseNormal, Reason: "connection closed"}
+	}
+
+	return ws.writeRaw(op, payload)
+}
+
+// writeRaw 编码并写入帧，不检查关闭状态。
+func (ws *WSConn[I, O]) writeRaw(op byte, payload []byte) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	frame := make([]byte, 0, len(payload)+10)
+	frame = append(frame, 0x80|op)
+
+	switch n := len(payload); {
+	case n <= 125:
+		frame = append(frame, byte(n))
+	case n <= 0xffff:
+		frame = binary.BigEndian.AppendUint16(append(frame, 126), uint16(n))
+	default:
+		frame = binary.BigEndian.AppendUint64(append(frame, 127), uint64(n))
+	}
+
+	if ws.cfg.WriteTimeout > 0 {
+		_ = ws.conn.SetWriteDeadline(time.Now().Add(ws.cfg.WriteTimeout))
+	}
+
+	_, err := ws.conn.Write(append(frame, payload...))
+	return err
+}
+
+// keepalive 定时发送 ping，返回停止函数。
+func (ws *WSConn[I, O]) keepalive() (stop func()) {
+	if ws.cfg.PingInterval <= 0 {
+		return func() {}
+	}
+
+	_ = ws.conn.SetReadDeadline(time.Now().Add(2 * ws.cfg.PingInterval))
+	done := make(chan struct{})
+	ticker := time.NewTicker(ws.cfg.PingInterval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if ws.Ping() != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// WebSocket 创建一个强类型的 WebSocket 处理器，通过 Router.WS 注册 (支持 OpenAPI)
+//
+// 握手失败时交由 ErrorHandler 处理；处理器返回 nil 时以 1000 关闭连接，返回错误时以 1011 关闭，
+// 返回 *WSCloseError 时使用其中的关闭码。处理器中的 panic 会先以 1011 关闭连接，再交由 Recovery 处理。
+func WebSocket[I any, O any](f func(c *Ctx, conn *WSConn[I, O]) error, config ...WSConfig) Handler {
+	cfg := DefaultWSConfig()
+	if len(config) > 0 {
+		cfg = config[0].withDefaults()
+	}
+
+	h := He(func(c *Ctx) error {
+		conn, br, protocol, err := wsUpgrade(c, &cfg)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		ws := &WSConn[I, O]{conn: conn, br: br, cfg: &cfg, json: c.engine.json, subprotocol: protocol}
+		stop := ws.keepalive()
+		defer stop()
+
+		err = func() error {
+			defer func() {
+				if p := recover(); p != nil {
+					_ = ws.Close(WSCloseInternalError, "")
+					panic(p)
+				}
+			}()
+			return f(c, ws)
+		}()
+
+		var ce *WSCloseError
+		switch {
+		case err == nil:
+			_ = ws.Close(WSCloseNormal, "")
+		case errors.As(err, &ce):
+			_ = ws.Close(ce.Code, ce.Reason)
+		default:
+			_ = ws.Close(WSCloseInternalError, "")
+			_ = c.ctx.Error(err) // 由 Logger 记录
+		}
+
+		if cfg.OnClose != nil {
+			cfg.OnClose(c, err)
+		}
+
+		return nil
+	})
+
+	if a, ok := hMeta.Get(h); ok {
+		a.Messages = []reflect.Type{reflect.TypeFor[I](), reflect.TypeFor[O]()}
+		a.Func = funcName(f)
+	}
+
+	return h
+}
+
+// wsUpgrade 校验握手请求并接管连接 (RFC 6455 4.2)
+func wsUpgrade(c *Ctx, cfg *WSConfig) (net.Conn, *bufio.Reader, string, error) {
+	if c.Method() != http.MethodGet ||
+		!headerContains(c.Request.Header, HeaderConnection, "upgrade") ||
+		!headerContains(c.Request.Header, HeaderUpgrade, "websocket") {
+		return nil, nil, "", ErrBadRequest("websocket: not a websocket handshake")
+	}
+
+	if c.GetHeader(HeaderSecWebSocketVersion) != "13" {
+		c.Header(HeaderSecWebSocketVersion, "13")
+		return nil, nil, "", ErrUpgradeRequired("websocket: unsupported version")
+	}
+
+	key := c.GetHeader(HeaderSecWebSocketKey)
+	if b, err := base64.StdEncoding.DecodeString(key); err != nil || len(b) != 16 {
+		return nil, nil, "", ErrBadRequest("websocket: invalid Sec-WebSocket-Key")
+	}
+
+	checkOrigin := cfg.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = sameOrigin
+	}
+	if !checkOrigin(c) {
+		return nil, nil, "", ErrForbidden("websocket: origin not allowed")
+	}
+
+	var protocol string
+	for _, p := range strings.Split(c.GetHeader(HeaderSecWebSocketProtocol), ",") {
+		if p = strings.TrimSpace(p); p != "" && protocol == "" {
+			for _, sp := range cfg.Subprotocols {
+				if sp == p {
+					protocol = p
+					break
+				}
+			}
+		}
+	}
+
+	c.Writer.WriteHeader(http.StatusSwitchingProtocols) // 供 Logger 记录状态码
+	conn, rw, err := c.Writer.Hijack()
+	if err != nil {
+		return nil, nil, "", ErrInternalServerError("websocket: " + err.Error())
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	sum := sha1.Sum([]byte(key + wsGUID))
+	var sb strings.Builder
+	sb.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+	sb.WriteString("Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n")
+	if protocol != "" {
+		sb.WriteString("Sec-WebSocket-Protocol: " + protocol + "\r\n")
+	}
+	for k, values := range c.Writer.Header() { // 保留已设置的响应头，如 X-Request-ID。
+		if k == HeaderVary || strings.HasPrefix(k, "Content-") {
+			continue
+		}
+		for _, v := range values {
+			sb.WriteString(k + ": " + v + "\r\n")
+		}
+	}
+	sb.WriteString("\r\n")
+
+	if _, err = conn.Write([]byte(sb.String())); err != nil {
+		_ = conn.Close()
+		return nil, nil, "", err
+	}
+
+	return conn, rw.Reader, protocol, nil
+}
+
+// sameOrigin 仅允许没有 Origin 头或 Origin 与 Host 相同的请求
+func sameOrigin(c *Ctx) bool {
+	origin := c.GetHeader(HeaderOrigin)
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, c.Request.Host)
+}
+
+// headerContains 检查以逗号分隔的请求头中是否包含 token (不区分大小写)
+func headerContains(h http.Header, key, token string) bool {
+	for _, v := range h.Values(key) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
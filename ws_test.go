@@ -0,0 +1,221 @@
+package sgin
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// wsClient 是测试用的最小 WebSocket 客户端
+type wsClient struct {
+	t    *testing.T
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+func wsServer(t *testing.T, f func(c *Ctx, conn *WSConn[any, any]) error, config ...WSConfig) *wsClient {
+	t.Helper()
+	cfg := WSConfig{ReadLimit: 1 << 10}
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	e := testEngine()
+	e.WS("/ws", WebSocket(f, cfg))
+	srv := httptest.NewServer(e.Gin())
+	t.Cleanup(srv.Close)
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	_, _ = io.WriteString(conn, "GET /ws HTTP/1.1\r\nHost: "+srv.Listener.Addr().String()+
+		"\r\nConnection: Upgrade\r\nUpgrade: websocket\r\nSec-WebSocket-Version: 13\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n")
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols ||
+		resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("handshake failed: %s %v", resp.Status, resp.Header)
+	}
+
+	return &wsClient{t: t, conn: conn, br: br}
+}
+
+// send 写入一个带掩码的帧
+func (c *wsClient) send(fin bool, op byte, payload []byte) {
+	b0 := op
+	if fin {
+		b0 |= 0x80
+	}
+	frame := []byte{b0, 0x80 | byte(len(payload))}
+	mask := []byte{1, 2, 3, 4}
+	frame = append(frame, mask...)
+	for i, v := range payload {
+		frame = append(frame, v^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// recv 读取一个服务端帧 (负载不超过 125 字节)
+func (c *wsClient) recv() (op byte, payload []byte) {
+	var h [2]byte
+	if _, err := io.ReadFull(c.br, h[:]); err != nil {
+		c.t.Fatal(err)
+	}
+	payload = make([]byte, h[1]&0x7f)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		c.t.Fatal(err)
+	}
+	return h[0] & 0x0f, payload
+}
+
+// expectClose 断言服务端回复了 code 关闭帧
+func (c *wsClient) expectClose(code int) string {
+	c.t.Helper()
+	op, payload := c.recv()
+	if op != wsOpClose || len(payload) < 2 {
+		c.t.Fatalf("expected close frame, got op=%d payload=%q", op, payload)
+	}
+	if got := int(binary.BigEndian.Uint16(payload)); got != code {
+		c.t.Fatalf("close code = %d, want %d", got, code)
+	}
+	return string(payload[2:])
+}
+
+func closePayload(code uint16, reason string) []byte {
+	return append(binary.BigEndian.AppendUint16(nil, code), reason...)
+}
+
+func wsEcho(c *Ctx, conn *WSConn[any, any]) error {
+	for {
+		isBinary, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		if err = conn.WriteMessage(isBinary, data); err != nil {
+			return err
+		}
+	}
+}
+
+func TestWSEchoAndPing(t *testing.T) {
+	c := wsServer(t, wsEcho)
+
+	c.send(true, wsOpPing, []byte("p"))
+	if op, payload := c.recv(); op != wsOpPong || string(payload) != "p" {
+		t.Fatalf("op=%d payload=%q", op, payload)
+	}
+
+	c.send(false, wsOpText, []byte("he"))
+	c.send(true, wsOpPing, nil) // 控制帧可以出现在分片消息中间
+	if op, _ := c.recv(); op != wsOpPong {
+		t.Fatalf("op = %d", op)
+	}
+	c.send(true, wsOpContinuation, []byte("llo"))
+	if op, payload := c.recv(); op != wsOpText || string(payload) != "hello" {
+		t.Fatalf("op=%d payload=%q", op, payload)
+	}
+
+	c.send(true, wsOpClose, closePayload(WSCloseGoingAway, "bye"))
+	c.expectClose(WSCloseGoingAway)
+}
+
+func TestWSCloseFrames(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload []byte
+		code    int
+	}{
+		{"empty", nil, WSCloseNormal},
+		{"normal", closePayload(WSCloseNormal, "ok"), WSCloseNormal},
+		{"application", closePayload(4000, ""), 4000},
+		{"one byte", []byte{0x03}, WSCloseProtocolError},
+		{"below 1000", closePayload(999, ""), WSCloseProtocolError},
+		{"no status", closePayload(WSCloseNoStatus, ""), WSCloseProtocolError},
+		{"abnormal", closePayload(WSCloseAbnormal, ""), WSCloseProtocolError},
+		{"tls", closePayload(1015, ""), WSCloseProtocolError},
+		{"reserved", closePayload(2000, ""), WSCloseProtocolError},
+		{"invalid utf-8", closePayload(WSCloseNormal, "\xff\xfe"), WSCloseProtocolError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := wsServer(t, wsEcho)
+			c.send(true, wsOpClose, tt.payload)
+			c.expectClose(tt.code)
+		})
+	}
+}
+
+func TestWSProtocolErrors(t *testing.T) {
+	c := wsServer(t, wsEcho)
+	c.send(false, wsOpPing, nil) // 控制帧不能分片
+	c.expectClose(WSCloseProtocolError)
+
+	c = wsServer(t, wsEcho)
+	c.send(true, wsOpContinuation, []byte("x"))
+	c.expectClose(WSCloseProtocolError)
+
+	c = wsServer(t, wsEcho)
+	c.send(true, wsOpText, []byte("\xff"))
+	c.expectClose(WSCloseInvalidPayload)
+
+	c = wsServer(t, wsEcho)
+	_, _ = c.conn.Write([]byte{0x81, 0x01, 'x'}) // 客户端帧必须带掩码
+	c.expectClose(WSCloseProtocolError)
+}
+
+func TestWSCloseReasonTruncation(t *testing.T) {
+	reason := "a" + strings.Repeat("é", 100) // 201 字节，第 123 字节位于字符中间。
+	c := wsServer(t, func(c *Ctx, conn *WSConn[any, any]) error {
+		return &WSCloseError{Code: 4001, Reason: reason}
+	})
+
+	got := c.expectClose(4001)
+	if len(got) > 123 || !utf8.ValidString(got) || !strings.HasPrefix(reason, got) {
+		t.Fatalf("reason = %q (%d bytes)", got, len(got))
+	}
+}
+
+func TestWSReadLimit(t *testing.T) {
+	// 未设置 ReadLimit 时使用默认值，客户端声明的超大帧在分配内存之前被拒绝。
+	c := wsServer(t, wsEcho, WSConfig{Subprotocols: []string{"chat"}})
+	_, _ = c.conn.Write([]byte{0x82, 0xff, 0x40, 0, 0, 0, 0, 0, 0, 0, 1, 2, 3, 4})
+	c.expectClose(WSCloseMessageTooBig)
+
+	// 分片消息的总长度超过限制
+	c = wsServer(t, wsEcho)
+	c.send(false, wsOpBinary, make([]byte, 100))
+	header := []byte{wsOpContinuation | 0x80, 0x80 | 126}
+	header = binary.BigEndian.AppendUint16(header, 1000)
+	_, _ = c.conn.Write(append(header, 1, 2, 3, 4))
+	c.expectClose(WSCloseMessageTooBig)
+}
+
+func TestWSConfigDefaults(t *testing.T) {
+	def := DefaultWSConfig()
+	if cfg := (WSConfig{}).withDefaults(); cfg.ReadLimit != def.ReadLimit || cfg.PingInterval != def.PingInterval || cfg.WriteTimeout != def.WriteTimeout {
+		t.Fatalf("zero config = %+v", cfg)
+	}
+
+	cfg := WSConfig{ReadLimit: -1, PingInterval: -1, WriteTimeout: time.Second}.withDefaults()
+	if cfg.ReadLimit != def.ReadLimit || cfg.PingInterval != -1 || cfg.WriteTimeout != time.Second {
+		t.Fatalf("config = %+v", cfg)
+	}
+}